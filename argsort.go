@@ -0,0 +1,113 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slices
+
+import (
+	"cmp"
+)
+
+// ArgSort returns the permutation which sorts list in ascending order,
+// list[perm[i]] is the i-th smallest element. list itself is not modified.
+func ArgSort[E cmp.Ordered](list []E) []int {
+	return argSort(list, false)
+}
+
+// ArgSortStable is like ArgSort, but indexes of equal elements are kept in
+// ascending order.
+func ArgSortStable[E cmp.Ordered](list []E) []int {
+	return argSort(list, true)
+}
+
+func argSort[E cmp.Ordered](list []E, stable bool) []int {
+	keys := make([]keyed[E], len(list))
+	for i := 0; i < len(list); i++ {
+		keys[i] = keyed[E]{key: list[i], idx: i}
+	}
+	if stable {
+		keyedOrder[E]{}.sortStable(keys, false)
+	} else {
+		keyedOrder[E]{}.sortFast(keys)
+	}
+	perm := make([]int, len(keys))
+	for i := 0; i < len(keys); i++ {
+		perm[i] = keys[i].idx
+	}
+	return perm
+}
+
+// The general version of ArgSort.
+func (od *Order[E]) ArgSort(list []E) []int {
+	return od.argSort(list, false)
+}
+
+// The general version of ArgSortStable.
+func (od *Order[E]) ArgSortStable(list []E) []int {
+	return od.argSort(list, true)
+}
+
+func (od *Order[E]) argSort(list []E, stable bool) []int {
+	perm := make([]int, len(list))
+	if len(list) < 2 {
+		for i := 0; i < len(perm); i++ {
+			perm[i] = i
+		}
+		return perm
+	}
+	// Sort by pointer list, then get indexes from the pointers.
+	ref := refsOf(list)
+	od.sortRefs(ref, stable, nil)
+	for i := 0; i < len(ref); i++ {
+		perm[i] = ptrDiff(ref[i], &list[0])
+	}
+	return perm
+}
+
+// ApplyPermutation reorders list so that the i-th element becomes the
+// original list[perm[i]]. It works with the result of ArgSort, and can be used
+// to sort several parallel slices by one of them. perm is not modified.
+// It panics if perm is not a permutation of the same length as list.
+func ApplyPermutation[E any](list []E, perm []int) {
+	if len(perm) != len(list) {
+		panic("slices.ApplyPermutation: length mismatch")
+	}
+	// Check all entries first, so an invalid perm leaves list untouched.
+	done := make([]uint64, (len(perm)+63)/64)
+	for _, j := range perm {
+		if uint(j) >= uint(len(perm)) || done[j/64]&(1<<(j%64)) != 0 {
+			panic("slices.ApplyPermutation: invalid permutation")
+		}
+		done[j/64] |= 1 << (j % 64)
+	}
+	clear(done)
+	for i := 0; i < len(perm); i++ {
+		if done[i/64]&(1<<(i%64)) != 0 {
+			continue
+		}
+		done[i/64] |= 1 << (i % 64)
+		k, j, tmp := i, perm[i], list[i]
+		for j != i {
+			done[j/64] |= 1 << (j % 64)
+			list[k] = list[j]
+			k, j = j, perm[j]
+		}
+		list[k] = tmp
+	}
+}
+
+// InversePermutation returns the inverse of perm, which satisfies
+// inv[perm[i]] == i. It panics if perm is not a permutation.
+func InversePermutation(perm []int) []int {
+	inv := make([]int, len(perm))
+	for i := 0; i < len(inv); i++ {
+		inv[i] = -1
+	}
+	for i, j := range perm {
+		if uint(j) >= uint(len(inv)) || inv[j] >= 0 {
+			panic("slices.InversePermutation: invalid permutation")
+		}
+		inv[j] = i
+	}
+	return inv
+}
@@ -0,0 +1,152 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slices
+
+import (
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+func checkArgSort[E any](t *testing.T, name string, list []E, perm []int, od *Order[E], stable bool) {
+	t.Helper()
+	if len(perm) != len(list) {
+		t.Fatalf("%s got %d indexes, want %d", name, len(perm), len(list))
+	}
+	seen := make([]bool, len(list))
+	for i, j := range perm {
+		if seen[j] {
+			t.Fatalf("%s got duplicate index %d", name, j)
+		}
+		seen[j] = true
+		if i == 0 {
+			continue
+		}
+		prev := perm[i-1]
+		if od.Less(list[j], list[prev]) {
+			t.Fatalf("%s didn't sort at %d", name, i)
+		}
+		if stable && !od.Less(list[prev], list[j]) && prev > j {
+			t.Fatalf("%s wasn't stable at %d", name, i)
+		}
+	}
+}
+
+func TestArgSort(t *testing.T) {
+	data := make([]int, 10000)
+	for i := range data {
+		data[i] = rand.Intn(100)
+	}
+	backup := Clone(data)
+	checkArgSort(t, "ArgSort", data, ArgSort(data), &intOrder, false)
+	checkArgSort(t, "ArgSortStable", data, ArgSortStable(data), &intOrder, true)
+	checkArgSort(t, "Order.ArgSort", data, intOrder.ArgSort(data), &intOrder, false)
+	checkArgSort(t, "Order.ArgSortStable", data, intCompareOrder.ArgSortStable(data), &intOrder, true)
+	if !Equal(data, backup) {
+		t.Errorf("ArgSort modified list")
+	}
+
+	objs := make([]bigObject, 1000)
+	for i := range objs {
+		objs[i].val = rand.Intn(100)
+	}
+	od := Order[bigObject]{
+		RefLess: func(a, b *bigObject) bool {
+			return a.val < b.val
+		},
+	}
+	check := Order[bigObject]{
+		Less: func(a, b bigObject) bool {
+			return a.val < b.val
+		},
+	}
+	checkArgSort(t, "Order.ArgSort", objs, od.ArgSort(objs), &check, false)
+	checkArgSort(t, "Order.ArgSortStable", objs, od.ArgSortStable(objs), &check, true)
+
+	if perm := intOrder.ArgSort([]int{1}); !Equal(perm, []int{0}) {
+		t.Errorf("Order.ArgSort got %v on single element", perm)
+	}
+}
+
+func TestApplyPermutation(t *testing.T) {
+	// Sort parallel columns by the key column.
+	n := 1000
+	keys := make([]int, n)
+	names := make([]string, n)
+	for i := range keys {
+		keys[i] = rand.Intn(n)
+		names[i] = strconv.Itoa(keys[i])
+	}
+	origin := Clone(keys)
+	perm := ArgSortStable(keys)
+	inv := InversePermutation(perm)
+	backup := Clone(perm)
+	ApplyPermutation(keys, perm)
+	ApplyPermutation(names, perm)
+	if !Equal(perm, backup) {
+		t.Errorf("ApplyPermutation modified perm")
+	}
+	if !IsSorted(keys) {
+		t.Errorf("ApplyPermutation didn't sort by ArgSort")
+	}
+	for i := range keys {
+		if names[i] != strconv.Itoa(keys[i]) {
+			t.Fatalf("columns mismatch at %d", i)
+		}
+	}
+
+	// Restore the original order by the inverse permutation.
+	ApplyPermutation(keys, inv)
+	if !Equal(keys, origin) {
+		t.Errorf("ApplyPermutation didn't restore by InversePermutation")
+	}
+	for i, j := range perm {
+		if inv[j] != i {
+			t.Fatalf("InversePermutation mismatch at %d", j)
+		}
+	}
+	ApplyPermutation(names, inv)
+	for i := range keys {
+		if names[i] != strconv.Itoa(keys[i]) {
+			t.Fatalf("columns mismatch at %d after restoring", i)
+		}
+	}
+
+	expectPanic := func(want string, fn func()) {
+		t.Helper()
+		defer func() {
+			t.Helper()
+			if r := recover(); r != want {
+				t.Errorf("got panic %v, want %q", r, want)
+			}
+		}()
+		fn()
+	}
+	const (
+		errLength  = "slices.ApplyPermutation: length mismatch"
+		errApply   = "slices.ApplyPermutation: invalid permutation"
+		errInverse = "slices.InversePermutation: invalid permutation"
+	)
+	list := []int{1, 2, 3}
+	for _, perm := range [][]int{
+		{0, 1},       // too short
+		{1, 2, 1},    // duplicate
+		{0, 3, 1},    // out of range
+		{0, -1, 1},   // negative
+		{2, 0, 1, 0}, // too long
+	} {
+		want := errApply
+		if len(perm) != len(list) {
+			want = errLength
+		}
+		expectPanic(want, func() { ApplyPermutation(list, perm) })
+		if !Equal(list, []int{1, 2, 3}) {
+			t.Errorf("ApplyPermutation modified list with %v: %v", perm, list)
+		}
+	}
+	expectPanic(errInverse, func() { InversePermutation([]int{0, 0}) })
+	expectPanic(errInverse, func() { InversePermutation([]int{0, 2}) })
+	expectPanic(errInverse, func() { InversePermutation([]int{-1, 0}) })
+}
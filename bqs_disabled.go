@@ -13,3 +13,15 @@ import (
 func tryBlockIntroSort[E cmp.Ordered](x []E) bool {
 	return false
 }
+
+func tryBlockIntroSortByKey[K cmp.Ordered](keys []keyed[K]) bool {
+	return false
+}
+
+func tryParallelBlockIntroSort[E cmp.Ordered](x []E, pool *workerPool) bool {
+	return false
+}
+
+func tryBlockIntroSortCancel[E cmp.Ordered](x []E, done cancelSignal) (tried, ok bool) {
+	return false, false
+}
@@ -20,11 +20,40 @@ func tryBlockIntroSort[E cmp.Ordered](list []E) bool {
 		unsafe.Sizeof(elem) < 2 || len(list) < bqsSize {
 		return false
 	}
+	list = skipNaNs(list)
 	chance := log2Ceil(uint(len(list))) * 2
 	blockIntroSort(list, chance)
 	return true
 }
 
+// skipNaNs moves NaNs to the head of list, where cmp.Less puts them, and
+// returns the rest. Raw comparisons in block partition work on the rest like
+// cmp.Less, but cost less.
+func skipNaNs[E cmp.Ordered](list []E) []E {
+	n := 0
+	for i := 0; i < len(list); i++ {
+		if list[i] != list[i] {
+			list[n], list[i] = list[i], list[n]
+			n++
+		}
+	}
+	return list[n:]
+}
+
+// tryBlockIntroSortByKey works like tryBlockIntroSort on keyed values, but
+// checks the size of key instead of the whole.
+func tryBlockIntroSortByKey[K cmp.Ordered](keys []keyed[K]) bool {
+	var key K
+	var word uintptr
+	if unsafe.Sizeof(key) > unsafe.Sizeof(word) ||
+		unsafe.Sizeof(key) < 2 || len(keys) < bqsSize {
+		return false
+	}
+	chance := log2Ceil(uint(len(keys))) * 2
+	keyedOrder[K]{}.blockIntroSort(keys, chance)
+	return true
+}
+
 func blockIntroSort[E cmp.Ordered](list []E, chance int) {
 	for len(list) >= bqsSize {
 		if chance--; chance < 0 {
@@ -41,6 +70,78 @@ func blockIntroSort[E cmp.Ordered](list []E, chance int) {
 	introSort(list, chance)
 }
 
+// tryBlockIntroSortCancel is the cancellable version of tryBlockIntroSort,
+// ok reports whether it finished without cancellation.
+func tryBlockIntroSortCancel[E cmp.Ordered](list []E, done cancelSignal) (tried, ok bool) {
+	var elem E
+	var word uintptr
+	if unsafe.Sizeof(elem) > unsafe.Sizeof(word) ||
+		unsafe.Sizeof(elem) < 2 || len(list) < bqsSize {
+		return false, false
+	}
+	list = skipNaNs(list)
+	chance := log2Ceil(uint(len(list))) * 2
+	return true, blockIntroSortCancel(list, chance, done)
+}
+
+func blockIntroSortCancel[E cmp.Ordered](list []E, chance int, done cancelSignal) bool {
+	for len(list) > cancelChunk {
+		if isDone(done) {
+			return false
+		}
+		if chance--; chance < 0 {
+			heapSort(list)
+			return true
+		}
+		m := blockPartition(list)
+		if m < 0 {
+			return true
+		}
+		if !blockIntroSortCancel(list[m:], chance, done) {
+			return false
+		}
+		list = list[:m]
+	}
+	if isDone(done) {
+		return false
+	}
+	blockIntroSort(list, chance)
+	return true
+}
+
+func tryParallelBlockIntroSort[E cmp.Ordered](list []E, pool *workerPool) bool {
+	var elem E
+	var word uintptr
+	if unsafe.Sizeof(elem) > unsafe.Sizeof(word) ||
+		unsafe.Sizeof(elem) < 2 || len(list) < bqsSize {
+		return false
+	}
+	list = skipNaNs(list)
+	chance := log2Ceil(uint(len(list))) * 2
+	parallelBlockIntroSort(list, chance, pool)
+	return true
+}
+
+func parallelBlockIntroSort[E cmp.Ordered](list []E, chance int, pool *workerPool) {
+	if len(list) <= parallelSize {
+		blockIntroSort(list, chance)
+		return
+	}
+	if chance--; chance < 0 {
+		heapSort(list)
+		return
+	}
+	m := blockPartition(list)
+	if m < 0 {
+		return
+	}
+	pool.fork(func() {
+		parallelBlockIntroSort(list[m:], chance, pool)
+	}, func() {
+		parallelBlockIntroSort(list[:m], chance, pool)
+	})
+}
+
 func compGE[E cmp.Ordered](a, b E) int {
 	if a < b {
 		return 0
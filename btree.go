@@ -0,0 +1,443 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slices
+
+import (
+	"cmp"
+	"unsafe"
+)
+
+// btreeNodeLines is the number of cache lines a node's elements span.
+const btreeNodeLines = 8
+
+// BTree is an in-memory B+tree holding a set of elements in ascending order.
+// Every node records the number of elements under each child, so elements can
+// be located by rank in O(log n) time. Leaves are linked for fast iteration.
+type BTree[E any] struct {
+	root       *btreeNode[E]
+	size       int
+	leafCap    int // max number of elements in leaf
+	innerCap   int // max number of children in inner node
+	search     func(list []E, x E) (int, bool)
+	lowerBound func(list []E, x E) int
+	upperBound func(list []E, x E) int
+	isSorted   func(list []E) bool
+}
+
+// btreeNode is a leaf when kids is nil.
+// In inner node, keys[i] separates kids[i] and kids[i+1]: elements under
+// kids[i] are less than it, and elements under kids[i+1] are not.
+type btreeNode[E any] struct {
+	keys   []E             // elements in leaf, separators in inner node
+	kids   []*btreeNode[E] // children of inner node
+	counts []int           // number of elements under each child
+	next   *btreeNode[E]   // next leaf
+}
+
+func (n *btreeNode[E]) size() int {
+	if n.kids == nil {
+		return len(n.keys)
+	}
+	total := 0
+	for _, c := range n.counts {
+		total += c
+	}
+	return total
+}
+
+// NewBTree creates an empty BTree.
+func NewBTree[E cmp.Ordered]() *BTree[E] {
+	t := &BTree[E]{
+		search:     binarySearch[E],
+		lowerBound: lowerBound[E],
+		upperBound: upperBound[E],
+		isSorted:   isSorted[E],
+	}
+	t.init()
+	return t
+}
+
+// The general version of NewBTree.
+func (od *Order[E]) NewBTree() *BTree[E] {
+	t := &BTree[E]{}
+	switch od.kind() {
+	case kindLess:
+		algo := lessFunc[E](od.Less)
+		t.search, t.lowerBound, t.upperBound, t.isSorted =
+			algo.binarySearch, algo.lowerBound, algo.upperBound, algo.isSorted
+	case kindRefLess:
+		algo := refLessFunc[E](od.RefLess)
+		t.search, t.lowerBound, t.upperBound, t.isSorted =
+			algo.binarySearch, algo.lowerBound, algo.upperBound, algo.isSorted
+	case kindCompare:
+		algo := compareFunc[E](od.Compare)
+		t.search, t.lowerBound, t.upperBound, t.isSorted =
+			algo.binarySearch, algo.lowerBound, algo.upperBound, algo.isSorted
+	default:
+		algo := refCompareFunc[E](od.RefCompare)
+		t.search, t.lowerBound, t.upperBound, t.isSorted =
+			algo.binarySearch, algo.lowerBound, algo.upperBound, algo.isSorted
+	}
+	t.init()
+	return t
+}
+
+// init derives node capacities from cache line size, so that the elements
+// of a node fill a few cache lines.
+func (t *BTree[E]) init() {
+	var elem E
+	elemSize := max(int(unsafe.Sizeof(elem)), 1)
+	wordSize := int(unsafe.Sizeof(uintptr(0)))
+	nodeSize := cacheInfo.lineSize * btreeNodeLines
+	t.leafCap = min(max(nodeSize/elemSize, 8), 256)
+	t.innerCap = min(max(nodeSize/(elemSize+wordSize*2), 8), 256)
+	t.root = t.newLeaf()
+}
+
+// Capacities leave room for one more element, which is inserted before split.
+func (t *BTree[E]) newLeaf() *btreeNode[E] {
+	return &btreeNode[E]{keys: make([]E, 0, t.leafCap+1)}
+}
+
+func (t *BTree[E]) newInner() *btreeNode[E] {
+	return &btreeNode[E]{
+		keys:   make([]E, 0, t.innerCap),
+		kids:   make([]*btreeNode[E], 0, t.innerCap+1),
+		counts: make([]int, 0, t.innerCap+1),
+	}
+}
+
+// Len returns the number of elements.
+func (t *BTree[E]) Len() int {
+	return t.size
+}
+
+// Insert adds x, or replaces the element equal to x. It reports whether x is
+// newly added.
+func (t *BTree[E]) Insert(x E) bool {
+	added, right, sep := t.insert(t.root, x)
+	if right != nil {
+		left := t.root
+		t.root = t.newInner()
+		t.root.keys = append(t.root.keys, sep)
+		t.root.kids = append(t.root.kids, left, right)
+		t.root.counts = append(t.root.counts, left.size(), right.size())
+	}
+	if added {
+		t.size++
+	}
+	return added
+}
+
+// insert returns the new right sibling and the separator when n is split.
+func (t *BTree[E]) insert(n *btreeNode[E], x E) (added bool, right *btreeNode[E], sep E) {
+	if n.kids == nil {
+		i, found := t.search(n.keys, x)
+		if found {
+			n.keys[i] = x
+			return false, nil, sep
+		}
+		n.keys = Insert(n.keys, i, x)
+		if len(n.keys) > t.leafCap {
+			right, sep = t.splitLeaf(n)
+		}
+		return true, right, sep
+	}
+
+	i := t.upperBound(n.keys, x)
+	added, kid, kidSep := t.insert(n.kids[i], x)
+	if added {
+		n.counts[i]++
+	}
+	if kid != nil {
+		c := kid.size()
+		n.counts[i] -= c
+		n.keys = Insert(n.keys, i, kidSep)
+		n.kids = Insert(n.kids, i+1, kid)
+		n.counts = Insert(n.counts, i+1, c)
+		if len(n.kids) > t.innerCap {
+			right, sep = t.splitInner(n)
+		}
+	}
+	return added, right, sep
+}
+
+func (t *BTree[E]) splitLeaf(n *btreeNode[E]) (*btreeNode[E], E) {
+	half := len(n.keys) / 2
+	right := t.newLeaf()
+	right.keys = append(right.keys, n.keys[half:]...)
+	n.keys = truncate(n.keys, half)
+	right.next, n.next = n.next, right
+	return right, right.keys[0]
+}
+
+func (t *BTree[E]) splitInner(n *btreeNode[E]) (*btreeNode[E], E) {
+	half := len(n.kids) / 2
+	right := t.newInner()
+	sep := n.keys[half-1]
+	right.keys = append(right.keys, n.keys[half:]...)
+	right.kids = append(right.kids, n.kids[half:]...)
+	right.counts = append(right.counts, n.counts[half:]...)
+	n.keys = truncate(n.keys, half-1)
+	n.kids = truncate(n.kids, half)
+	n.counts = n.counts[:half]
+	return right, sep
+}
+
+// truncate cuts list to size n, and zeroes the dropped elements so objects
+// they reference can be garbage collected.
+func truncate[E any](list []E, n int) []E {
+	clear(list[n:])
+	return list[:n]
+}
+
+// cut removes list[a:b] like Delete, and zeroes the dropped tail.
+func cut[E any](list []E, a, b int) []E {
+	n := a + copy(list[a:], list[b:])
+	return truncate(list, n)
+}
+
+// Delete removes the element equal to x, and returns it with whether there
+// is one.
+func (t *BTree[E]) Delete(x E) (E, bool) {
+	old, found := t.delete(t.root, x)
+	if found {
+		t.size--
+		if t.root.kids != nil && len(t.root.kids) == 1 {
+			t.root = t.root.kids[0]
+		}
+	}
+	return old, found
+}
+
+func (t *BTree[E]) delete(n *btreeNode[E], x E) (old E, found bool) {
+	if n.kids == nil {
+		var i int
+		if i, found = t.search(n.keys, x); !found {
+			return old, false
+		}
+		old = n.keys[i]
+		n.keys = cut(n.keys, i, i+1)
+		return old, true
+	}
+
+	i := t.upperBound(n.keys, x)
+	old, found = t.delete(n.kids[i], x)
+	if found {
+		n.counts[i]--
+		if kid := n.kids[i]; (kid.kids == nil && len(kid.keys) < t.leafCap/2) ||
+			(kid.kids != nil && len(kid.kids) < t.innerCap/2) {
+			t.rebalance(n, i)
+		}
+	}
+	return old, found
+}
+
+// rebalance fixes the underflowed i-th child of n, by merging it with a
+// sibling, or moving elements from the sibling.
+func (t *BTree[E]) rebalance(n *btreeNode[E], i int) {
+	if i == len(n.kids)-1 {
+		i--
+	}
+	left, right := n.kids[i], n.kids[i+1]
+	if left.kids == nil {
+		if len(left.keys)+len(right.keys) <= t.leafCap {
+			left.keys = append(left.keys, right.keys...)
+			left.next = right.next
+			t.dropRight(n, i)
+			return
+		}
+		t.balanceLeaves(left, right)
+		n.keys[i] = right.keys[0]
+	} else {
+		if len(left.kids)+len(right.kids) <= t.innerCap {
+			left.keys = append(append(left.keys, n.keys[i]), right.keys...)
+			left.kids = append(left.kids, right.kids...)
+			left.counts = append(left.counts, right.counts...)
+			t.dropRight(n, i)
+			return
+		}
+		n.keys[i] = t.balanceInners(left, right, n.keys[i])
+	}
+	total := n.counts[i] + n.counts[i+1]
+	n.counts[i] = left.size()
+	n.counts[i+1] = total - n.counts[i]
+}
+
+// dropRight removes the (i+1)-th child of n, which is merged into the i-th.
+func (t *BTree[E]) dropRight(n *btreeNode[E], i int) {
+	n.counts[i] += n.counts[i+1]
+	n.keys = cut(n.keys, i, i+1)
+	n.kids = cut(n.kids, i+1, i+2)
+	n.counts = cut(n.counts, i+1, i+2)
+}
+
+// balanceLeaves moves elements between two adjacent leaves to make them
+// about the same size.
+func (t *BTree[E]) balanceLeaves(left, right *btreeNode[E]) {
+	if len(left.keys) < len(right.keys) {
+		k := (len(right.keys) - len(left.keys)) / 2
+		left.keys = append(left.keys, right.keys[:k]...)
+		right.keys = cut(right.keys, 0, k)
+	} else {
+		m := len(left.keys) - (len(left.keys)-len(right.keys))/2
+		right.keys = Insert(right.keys, 0, left.keys[m:]...)
+		left.keys = truncate(left.keys, m)
+	}
+}
+
+// balanceInners moves children between two adjacent inner nodes to make them
+// about the same size. sep is the old separator, the new one is returned.
+func (t *BTree[E]) balanceInners(left, right *btreeNode[E], sep E) E {
+	if len(left.kids) < len(right.kids) {
+		k := (len(right.kids) - len(left.kids)) / 2
+		left.keys = append(append(left.keys, sep), right.keys[:k-1]...)
+		left.kids = append(left.kids, right.kids[:k]...)
+		left.counts = append(left.counts, right.counts[:k]...)
+		sep = right.keys[k-1]
+		right.keys = cut(right.keys, 0, k)
+		right.kids = cut(right.kids, 0, k)
+		right.counts = cut(right.counts, 0, k)
+	} else {
+		m := len(left.kids) - (len(left.kids)-len(right.kids))/2
+		right.keys = Insert(right.keys, 0, sep)
+		right.keys = Insert(right.keys, 0, left.keys[m:]...)
+		right.kids = Insert(right.kids, 0, left.kids[m:]...)
+		right.counts = Insert(right.counts, 0, left.counts[m:]...)
+		sep = left.keys[m-1]
+		left.keys = truncate(left.keys, m-1)
+		left.kids = truncate(left.kids, m)
+		left.counts = left.counts[:m]
+	}
+	return sep
+}
+
+// Find returns the element equal to x, and whether there is one.
+func (t *BTree[E]) Find(x E) (E, bool) {
+	n := t.root
+	for n.kids != nil {
+		n = n.kids[t.upperBound(n.keys, x)]
+	}
+	if i, found := t.search(n.keys, x); found {
+		return n.keys[i], true
+	}
+	var zero E
+	return zero, false
+}
+
+// Contains reports whether x is in the BTree.
+func (t *BTree[E]) Contains(x E) bool {
+	_, found := t.Find(x)
+	return found
+}
+
+// Rank returns the number of elements less than x.
+func (t *BTree[E]) Rank(x E) int {
+	rank, n := 0, t.root
+	for n.kids != nil {
+		i := t.upperBound(n.keys, x)
+		for _, c := range n.counts[:i] {
+			rank += c
+		}
+		n = n.kids[i]
+	}
+	return rank + t.lowerBound(n.keys, x)
+}
+
+// Select returns the k-th smallest element, counting from 0.
+// It panics if k is out of range.
+func (t *BTree[E]) Select(k int) E {
+	if k < 0 || k >= t.size {
+		panic("slices.BTree: index out of range")
+	}
+	n, i := t.locate(k)
+	return n.keys[i]
+}
+
+// locate finds the leaf holding the k-th smallest element, and its position
+// in that leaf.
+func (t *BTree[E]) locate(k int) (*btreeNode[E], int) {
+	n := t.root
+	for n.kids != nil {
+		i := 0
+		for k >= n.counts[i] {
+			k -= n.counts[i]
+			i++
+		}
+		n = n.kids[i]
+	}
+	return n, k
+}
+
+// Ascend calls fn on elements in ascending order from the k-th smallest one,
+// until fn returns false.
+func (t *BTree[E]) Ascend(k int, fn func(x E) bool) {
+	if k >= t.size {
+		return
+	}
+	n, i := t.locate(max(k, 0))
+	for ; n != nil; n, i = n.next, 0 {
+		for ; i < len(n.keys); i++ {
+			if !fn(n.keys[i]) {
+				return
+			}
+		}
+	}
+}
+
+// Load replaces all elements with those in list, which must be sorted, like
+// the output of Sort. For equal elements, only the last one is kept.
+// It builds the tree in O(n) time, much faster than inserting one by one.
+// It panics if list is not sorted.
+func (t *BTree[E]) Load(list []E) {
+	if !t.isSorted(list) {
+		panic("slices.BTree.Load: list is not sorted")
+	}
+
+	var nodes []*btreeNode[E]
+	var counts []int
+	var mins []E
+	leaf := t.newLeaf()
+	for _, x := range list {
+		if last := len(leaf.keys) - 1; last >= 0 &&
+			t.lowerBound(leaf.keys[last:], x) == 0 {
+			leaf.keys[last] = x // equal to the last one
+			continue
+		}
+		if len(leaf.keys) == t.leafCap {
+			nodes = append(nodes, leaf)
+			counts = append(counts, len(leaf.keys))
+			mins = append(mins, leaf.keys[0])
+			leaf.next = t.newLeaf()
+			leaf = leaf.next
+		}
+		leaf.keys = append(leaf.keys, x)
+	}
+	if last := len(nodes) - 1; last >= 0 && len(leaf.keys) < t.leafCap/2 {
+		t.balanceLeaves(nodes[last], leaf)
+		counts[last] = len(nodes[last].keys)
+	}
+	nodes = append(nodes, leaf)
+	counts = append(counts, len(leaf.keys))
+	if len(leaf.keys) != 0 {
+		mins = append(mins, leaf.keys[0])
+	}
+
+	for len(nodes) > 1 {
+		m := (len(nodes) + t.innerCap - 1) / t.innerCap
+		for p := 0; p < m; p++ {
+			// distribute children evenly, no one underflows
+			a, b := len(nodes)*p/m, len(nodes)*(p+1)/m
+			n := t.newInner()
+			n.keys = append(n.keys, mins[a+1:b]...)
+			n.kids = append(n.kids, nodes[a:b]...)
+			n.counts = append(n.counts, counts[a:b]...)
+			nodes[p], counts[p], mins[p] = n, n.size(), mins[a]
+		}
+		clear(nodes[m:])
+		nodes, counts, mins = nodes[:m], counts[:m], truncate(mins, m)
+	}
+	t.root, t.size = nodes[0], counts[0]
+}
@@ -0,0 +1,211 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slices
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+// checkBTree verifies the structure of tr, and returns its elements.
+func checkBTree[E any](t *testing.T, tr *BTree[E], less func(a, b E) bool) []E {
+	t.Helper()
+	var walk func(n *btreeNode[E], root bool, depth int) int
+	leafDepth := -1
+	walk = func(n *btreeNode[E], root bool, depth int) int {
+		if n.kids == nil {
+			if leafDepth < 0 {
+				leafDepth = depth
+			} else if leafDepth != depth {
+				t.Fatalf("leaves at depth %d and %d", leafDepth, depth)
+			}
+			if !root && len(n.keys) < tr.leafCap/2 || len(n.keys) > tr.leafCap {
+				t.Fatalf("leaf with %d elements", len(n.keys))
+			}
+			return len(n.keys)
+		}
+		if !root && len(n.kids) < tr.innerCap/2 || len(n.kids) > tr.innerCap ||
+			len(n.kids) < 2 || len(n.keys) != len(n.kids)-1 || len(n.counts) != len(n.kids) {
+			t.Fatalf("inner node with %d keys and %d kids", len(n.keys), len(n.kids))
+		}
+		total := 0
+		for i, kid := range n.kids {
+			if c := walk(kid, false, depth+1); c != n.counts[i] {
+				t.Fatalf("count %d, want %d", n.counts[i], c)
+			}
+			total += n.counts[i]
+		}
+		return total
+	}
+	if c := walk(tr.root, true, 0); c != tr.Len() {
+		t.Fatalf("size %d, want %d", tr.Len(), c)
+	}
+
+	var list []E
+	tr.Ascend(0, func(x E) bool {
+		list = append(list, x)
+		return true
+	})
+	if len(list) != tr.Len() {
+		t.Fatalf("Ascend got %d elements, want %d", len(list), tr.Len())
+	}
+	for i := 1; i < len(list); i++ {
+		if !less(list[i-1], list[i]) {
+			t.Fatalf("elements out of order at %d", i)
+		}
+	}
+	return list
+}
+
+func TestBTree(t *testing.T) {
+	for _, caps := range [][2]int{{4, 4}, {5, 5}, {16, 8}, {0, 0}} {
+		t.Run(fmt.Sprintf("%dx%d", caps[0], caps[1]), func(t *testing.T) {
+			tr := NewBTree[int]()
+			if caps[0] != 0 {
+				tr.leafCap, tr.innerCap = caps[0], caps[1]
+				tr.root = tr.newLeaf()
+			}
+			testBTree(t, tr)
+		})
+	}
+}
+
+func testBTree(t *testing.T, tr *BTree[int]) {
+	n := 5000
+	less := func(a, b int) bool { return a < b }
+	set := make(map[int]bool)
+	for i := 0; i < n; i++ {
+		x := rand.Intn(n)
+		if tr.Insert(x) == set[x] {
+			t.Fatalf("Insert(%d) got %v", x, set[x])
+		}
+		set[x] = true
+	}
+	want := make([]int, 0, len(set))
+	for x := range set {
+		want = append(want, x)
+	}
+	Sort(want)
+	if got := checkBTree(t, tr, less); !Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	for x := -1; x <= n; x++ {
+		rank, found := BinarySearch(want, x)
+		if got := tr.Rank(x); got != rank {
+			t.Fatalf("Rank(%d) got %d, want %d", x, got, rank)
+		}
+		if y, ok := tr.Find(x); ok != found || (found && y != x) {
+			t.Fatalf("Find(%d) got (%d, %v)", x, y, ok)
+		}
+	}
+	for k, x := range want {
+		if got := tr.Select(k); got != x {
+			t.Fatalf("Select(%d) got %d, want %d", k, got, x)
+		}
+	}
+	var got []int
+	tr.Ascend(len(want)/2, func(x int) bool {
+		got = append(got, x)
+		return len(got) < 10
+	})
+	if !Equal(got, want[len(want)/2:][:10]) {
+		t.Errorf("Ascend got %v", got)
+	}
+
+	for i := 0; i < n; i++ {
+		x := rand.Intn(n)
+		if y, ok := tr.Delete(x); ok != set[x] || (ok && y != x) {
+			t.Fatalf("Delete(%d) got (%d, %v)", x, y, ok)
+		}
+		delete(set, x)
+		if i%500 == 0 {
+			checkBTree(t, tr, less)
+		}
+	}
+	if got := checkBTree(t, tr, less); len(got) != len(set) {
+		t.Fatalf("got %d elements, want %d", len(got), len(set))
+	}
+	for x := range set {
+		tr.Delete(x)
+	}
+	if tr.Len() != 0 || tr.root.kids != nil || tr.Contains(0) {
+		t.Fatalf("not empty after deleting all")
+	}
+	tr.Insert(1)
+	if checkBTree(t, tr, less); tr.Select(0) != 1 {
+		t.Fatalf("reused tree is broken")
+	}
+}
+
+func TestBTreeLoad(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+	for _, n := range []int{0, 1, 3, 4, 5, 17, 100, 1000, 10000} {
+		data := make([]int, n)
+		for i := range data {
+			data[i] = rand.Intn(n*3/4 + 1)
+		}
+		Sort(data)
+		want := Compact(Clone(data))
+		for _, caps := range [][2]int{{4, 4}, {5, 6}, {0, 0}} {
+			tr := NewBTree[int]()
+			if caps[0] != 0 {
+				tr.leafCap, tr.innerCap = caps[0], caps[1]
+			}
+			tr.Insert(-1)
+			tr.Load(data)
+			if got := checkBTree(t, tr, less); !Equal(got, want) {
+				t.Fatalf("Load(%d) with caps %v got %v, want %v", n, caps, got, want)
+			}
+			for _, x := range want[:len(want)/2] {
+				tr.Delete(x)
+			}
+			tr.Insert(n)
+			checkBTree(t, tr, less)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Load didn't panic on unsorted list")
+		}
+	}()
+	NewBTree[int]().Load([]int{2, 1})
+}
+
+func TestOrderBTree(t *testing.T) {
+	less := func(a, b intPair) bool { return a.a < b.a }
+	n := 1000
+	tr := intPairOrder.NewBTree()
+	tr.leafCap, tr.innerCap = 4, 4
+	tr.root = tr.newLeaf()
+	for i := 0; i < n; i++ {
+		tr.Insert(intPair{i % 100, i})
+	}
+	list := checkBTree(t, tr, less)
+	if len(list) != 100 {
+		t.Fatalf("got %d elements, want 100", len(list))
+	}
+	for i, x := range list {
+		if x.a != i || x.b != n-100+i {
+			t.Fatalf("got %v at %d, the last inserted one should be kept", x, i)
+		}
+	}
+
+	data := make([]intPair, n)
+	for i := range data {
+		data[i] = intPair{i / 10, i}
+	}
+	tr.Load(data)
+	for i, x := range checkBTree(t, tr, less) {
+		if x.a != i || x.b != i*10+9 {
+			t.Fatalf("Load got %v at %d, the last one should be kept", x, i)
+		}
+	}
+	if x, ok := tr.Delete(intPair{a: 50}); !ok || x.b != 509 || tr.Rank(intPair{a: 60}) != 59 {
+		t.Errorf("Delete got (%v, %v)", x, ok)
+	}
+}
@@ -0,0 +1,17 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build linux && !amd64
+
+package slices
+
+func init() {
+	lineSize, available := readCacheInfo("/sys")
+	if lineSize > 0 {
+		cacheInfo.lineSize = lineSize
+	}
+	if available > 0 {
+		cacheInfo.available = available
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build linux
+
+package slices
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// readCacheInfo gets cache topology of cpu0 from sysfs under root, it works
+// like the cpuid version in cache_amd64.go. Zero is returned for unknown value.
+func readCacheInfo(root string) (lineSize, available int) {
+	dirs, _ := filepath.Glob(filepath.Join(root, "devices/system/cpu/cpu0/cache/index*"))
+	lineLevel, l2Size, l3Size := 0, 0, 0
+	for _, dir := range dirs {
+		if kind, ok := readSysfs(dir, "type"); ok && kind == "Instruction" {
+			continue
+		}
+		str, _ := readSysfs(dir, "level")
+		level, err := strconv.Atoi(str)
+		if err != nil {
+			continue
+		}
+		str, _ = readSysfs(dir, "coherency_line_size")
+		if line, err := strconv.Atoi(str); err == nil && line > 0 &&
+			(lineLevel == 0 || level < lineLevel) {
+			lineSize, lineLevel = line, level
+		}
+		if level != 2 && level != 3 {
+			continue
+		}
+		str, _ = readSysfs(dir, "size")
+		size := parseCacheSize(str)
+		str, _ = readSysfs(dir, "shared_cpu_list")
+		cpus := countCPUList(str)
+		if size <= 0 || cpus <= 0 {
+			continue
+		}
+		if level == 2 {
+			l2Size = size / cpus
+		} else {
+			l3Size = size / cpus
+		}
+	}
+	available = max(l2Size, l3Size/2)
+	return lineSize, available
+}
+
+func readSysfs(dir, name string) (string, bool) {
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return "", false
+	}
+	return strings.TrimSpace(string(data)), true
+}
+
+// parseCacheSize parses size like "48K" or "32M", it returns -1 on error.
+func parseCacheSize(str string) int {
+	unit := 1
+	switch {
+	case strings.HasSuffix(str, "K"):
+		unit = 1024
+	case strings.HasSuffix(str, "M"):
+		unit = 1024 * 1024
+	case strings.HasSuffix(str, "G"):
+		unit = 1024 * 1024 * 1024
+	}
+	if unit != 1 {
+		str = str[:len(str)-1]
+	}
+	size, err := strconv.Atoi(str)
+	if err != nil || size < 0 {
+		return -1
+	}
+	return size * unit
+}
+
+// countCPUList counts cpus in list like "0-3,8-11", it returns -1 on error.
+func countCPUList(str string) int {
+	if str == "" {
+		return -1
+	}
+	count := 0
+	for _, part := range strings.Split(str, ",") {
+		first, last, isRange := strings.Cut(part, "-")
+		a, err := strconv.Atoi(first)
+		if err != nil || a < 0 {
+			return -1
+		}
+		b := a
+		if isRange {
+			if b, err = strconv.Atoi(last); err != nil || b < a {
+				return -1
+			}
+		}
+		count += b - a + 1
+	}
+	return count
+}
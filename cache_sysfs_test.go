@@ -0,0 +1,92 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build linux
+
+package slices
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// makeSysfs creates a fake sysfs tree with cache entries of cpu0.
+func makeSysfs(t *testing.T, caches []map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for i, cache := range caches {
+		dir := filepath.Join(root, "devices/system/cpu/cpu0/cache", fmt.Sprintf("index%d", i))
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		for name, value := range cache {
+			if err := os.WriteFile(filepath.Join(dir, name), []byte(value+"\n"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	return root
+}
+
+func TestReadCacheInfo(t *testing.T) {
+	cases := []struct {
+		name      string
+		caches    []map[string]string
+		lineSize  int
+		available int
+	}{
+		{"graviton", []map[string]string{
+			{"level": "1", "type": "Data", "size": "64K", "coherency_line_size": "64", "shared_cpu_list": "0"},
+			{"level": "1", "type": "Instruction", "size": "64K", "coherency_line_size": "32", "shared_cpu_list": "0"},
+			{"level": "2", "type": "Unified", "size": "1024K", "coherency_line_size": "64", "shared_cpu_list": "0"},
+			{"level": "3", "type": "Unified", "size": "32768K", "coherency_line_size": "64", "shared_cpu_list": "0-63"},
+		}, 64, 1024 * 1024},
+		{"shared-l2", []map[string]string{
+			{"level": "1", "type": "Data", "size": "32K", "coherency_line_size": "128", "shared_cpu_list": "0"},
+			{"level": "2", "type": "Unified", "size": "2M", "coherency_line_size": "128", "shared_cpu_list": "0-3"},
+			{"level": "3", "type": "Unified", "size": "32M", "coherency_line_size": "128", "shared_cpu_list": "0-3,8-11"},
+		}, 128, 2 * 1024 * 1024},
+		{"no-l3", []map[string]string{
+			{"level": "2", "size": "512K", "coherency_line_size": "64", "shared_cpu_list": "0-1"},
+		}, 64, 256 * 1024},
+		{"broken", []map[string]string{
+			{"level": "x", "size": "64K", "coherency_line_size": "64", "shared_cpu_list": "0"},
+			{"level": "2", "size": "big", "coherency_line_size": "0", "shared_cpu_list": "0"},
+			{"level": "3", "size": "8M", "shared_cpu_list": "3-1"},
+		}, 0, 0},
+		{"empty", nil, 0, 0},
+	}
+	for _, c := range cases {
+		root := makeSysfs(t, c.caches)
+		lineSize, available := readCacheInfo(root)
+		if lineSize != c.lineSize || available != c.available {
+			t.Errorf("%s: got (%d, %d), want (%d, %d)",
+				c.name, lineSize, available, c.lineSize, c.available)
+		}
+	}
+}
+
+func TestParseCacheSize(t *testing.T) {
+	for str, want := range map[string]int{
+		"48K": 48 * 1024, "32M": 32 * 1024 * 1024, "1G": 1024 * 1024 * 1024,
+		"4096": 4096, "": -1, "K": -1, "-1K": -1, "1.5M": -1,
+	} {
+		if got := parseCacheSize(str); got != want {
+			t.Errorf("parseCacheSize(%q) got %d, want %d", str, got, want)
+		}
+	}
+}
+
+func TestCountCPUList(t *testing.T) {
+	for str, want := range map[string]int{
+		"0": 1, "0-3": 4, "0-3,8-11": 8, "1,3,5-6": 4,
+		"": -1, "a": -1, "3-1": -1, "0-": -1, "0,,1": -1,
+	} {
+		if got := countCPUList(str); got != want {
+			t.Errorf("countCPUList(%q) got %d, want %d", str, got, want)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slices
+
+import (
+	"cmp"
+)
+
+// SortPaired sorts keys in ascending order, and moves elements in vals along
+// with keys. It panics if keys and vals have different lengths.
+func SortPaired[K cmp.Ordered, V any](keys []K, vals []V) {
+	if len(keys) != len(vals) {
+		panic("slices.SortPaired: length mismatch")
+	}
+	SortColumns(keys, func(i, j int) {
+		vals[i], vals[j] = vals[j], vals[i]
+	})
+}
+
+// SortStablePaired is like SortPaired, but keeps the original order of
+// equal keys. It needs no extra memory.
+func SortStablePaired[K cmp.Ordered, V any](keys []K, vals []V) {
+	if len(keys) != len(vals) {
+		panic("slices.SortStablePaired: length mismatch")
+	}
+	SortStableColumns(keys, func(i, j int) {
+		vals[i], vals[j] = vals[j], vals[i]
+	})
+}
+
+// SortColumns sorts keys in ascending order. Elements are only moved by
+// swapping, and swap(i, j) is called after keys[i] and keys[j] are swapped,
+// so other columns can follow the key column.
+func SortColumns[K cmp.Ordered](keys []K, swap func(i, j int)) {
+	if len(keys) < 2 {
+		return
+	}
+	columns[K]{keys: keys, swapFn: swap}.sortFast(keys)
+}
+
+// SortStableColumns is like SortColumns, but keeps the original order of
+// equal keys. It needs no extra memory.
+func SortStableColumns[K cmp.Ordered](keys []K, swap func(i, j int)) {
+	if len(keys) < 2 {
+		return
+	}
+	columns[K]{keys: keys, swapFn: swap}.sortStable(keys)
+}
+
+// columns is the specialization in zfunc_h.go, which moves elements only by
+// swapping. The list it works on is a part of keys.
+type columns[K cmp.Ordered] struct {
+	keys   []K
+	swapFn func(i, j int)
+}
+
+func (lt columns[K]) swap(list []K, i, j int) {
+	list[i], list[j] = list[j], list[i]
+	base := ptrDiff(&list[0], &lt.keys[0])
+	lt.swapFn(base+i, base+j)
+}
+
+func (lt columns[K]) reverse(list []K) {
+	for i, j := 0, len(list)-1; i < j; i, j = i+1, j-1 {
+		lt.swap(list, i, j)
+	}
+}
+
+// rotateLeft works like the one in slices.go.
+func (lt columns[K]) rotateLeft(list []K, r int) {
+	for r != 0 && r != len(list) {
+		if r*2 <= len(list) {
+			lt.swapRange(list, 0, len(list)-r, r)
+			list = list[:len(list)-r]
+		} else {
+			lt.swapRange(list, 0, r, len(list)-r)
+			list, r = list[len(list)-r:], r*2-len(list)
+		}
+	}
+}
+
+// swapRange swaps list[a:a+n] and list[b:b+n].
+func (lt columns[K]) swapRange(list []K, a, b, n int) {
+	for i := 0; i < n; i++ {
+		lt.swap(list, a+i, b+i)
+	}
+}
+
+// sortStable works like the one in sort_ordered.go, merging runs in place.
+func (lt columns[K]) sortStable(list []K) {
+	size := len(list)
+	powerMerge(size, lt.extendRun(list), func(a int) int {
+		return a + lt.extendRun(list[a:])
+	}, func(a, b, c int) bool {
+		lt.mergeInPlace(list[a:c], b-a)
+		return true
+	})
+}
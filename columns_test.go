@@ -0,0 +1,121 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slices
+
+import (
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+func TestSortPaired(t *testing.T) {
+	for _, gen := range pattern {
+		for _, n := range []int{0, 1, 10, 100, 10000} {
+			keys := make([]int, n)
+			gen.fn(keys)
+			vals := make([]string, n)
+			for i := range keys {
+				vals[i] = strconv.Itoa(keys[i])
+			}
+			want := Clone(keys)
+			Sort(want)
+			SortPaired(keys, vals)
+			if !Equal(keys, want) {
+				t.Fatalf("SortPaired mismatch with Sort on %s-%d", gen.name, n)
+			}
+			for i := range keys {
+				if vals[i] != strconv.Itoa(keys[i]) {
+					t.Fatalf("SortPaired mismatch between columns on %s-%d", gen.name, n)
+				}
+			}
+		}
+	}
+}
+
+func TestSortStablePaired(t *testing.T) {
+	for _, gen := range pattern {
+		for _, n := range []int{0, 1, 10, 100, 10000} {
+			keys := make([]int, n)
+			gen.fn(keys)
+			for i := range keys {
+				keys[i] %= n/10 + 1
+			}
+			vals := make([]int, n)
+			for i := range vals {
+				vals[i] = i
+			}
+			SortStablePaired(keys, vals)
+			if !IsSorted(keys) {
+				t.Fatalf("SortStablePaired didn't sort %s-%d", gen.name, n)
+			}
+			for i := 1; i < n; i++ {
+				if keys[i] == keys[i-1] && vals[i] < vals[i-1] {
+					t.Fatalf("SortStablePaired wasn't stable on %s-%d", gen.name, n)
+				}
+			}
+		}
+	}
+
+	// Natural runs are kept.
+	keys := make([]int, 1000)
+	for i := range keys {
+		keys[i] = i / 3
+	}
+	swaps := 0
+	SortStableColumns(keys, func(i, j int) { swaps++ })
+	if swaps != 0 {
+		t.Errorf("SortStableColumns swapped %d times on sorted keys", swaps)
+	}
+}
+
+func TestSortColumns(t *testing.T) {
+	n := 10000
+	keys := make([]float64, n)
+	col1 := make([]int, n)
+	col2 := make([]float64, n)
+	for i := range keys {
+		keys[i] = float64(rand.Intn(n))
+		col1[i] = int(keys[i])
+		col2[i] = -keys[i]
+	}
+	swap := func(i, j int) {
+		col1[i], col1[j] = col1[j], col1[i]
+		col2[i], col2[j] = col2[j], col2[i]
+	}
+	check := func(name string) {
+		t.Helper()
+		if !IsSorted(keys) {
+			t.Fatalf("%s didn't sort", name)
+		}
+		for i := range keys {
+			if float64(col1[i]) != keys[i] || col2[i] != -keys[i] {
+				t.Fatalf("%s mismatch between columns at %d", name, i)
+			}
+		}
+	}
+	SortColumns(keys, swap)
+	check("SortColumns")
+	Reverse(keys)
+	Reverse(col1)
+	Reverse(col2)
+	SortStableColumns(keys, swap)
+	check("SortStableColumns")
+}
+
+// It's hard to run heapSort from API, test it alone
+func TestColumnsHeapSort(t *testing.T) {
+	keys := make([]int, 1000)
+	vals := make([]int, len(keys))
+	for i := range keys {
+		keys[i] = rand.Intn(100)
+		vals[i] = keys[i]
+	}
+	columns[int]{keys: keys, swapFn: func(i, j int) {
+		vals[i], vals[j] = vals[j], vals[i]
+	}}.heapSort(keys)
+	if !IsSorted(keys) || !Equal(keys, vals) {
+		t.Errorf("heapSort didn't sort columns")
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slices
+
+import (
+	"cmp"
+	"context"
+)
+
+// Segments not longer than it are sorted without checking cancellation.
+const cancelChunk = 64 * 1024
+
+// cancelSignal is the type of context.Context.Done.
+type cancelSignal = <-chan struct{}
+
+func isDone(done cancelSignal) bool {
+	select {
+	case <-done:
+		return true
+	default:
+		return false
+	}
+}
+
+// SortContext is like Sort, but checks ctx periodically while sorting.
+// When ctx is done, it returns ctx.Err() and leaves list as a permutation of
+// the input, which is not sorted.
+func SortContext[E cmp.Ordered](ctx context.Context, list []E) error {
+	done := ctx.Done()
+	if done == nil {
+		Sort(list)
+		return nil
+	}
+	if len(list) <= cancelChunk {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		Sort(list)
+		return nil
+	}
+	if tried, ok := tryBlockIntroSortCancel(list, done); tried {
+		if !ok {
+			return ctx.Err()
+		}
+		return nil
+	}
+	if !sortFastCancel(list, done) {
+		return ctx.Err()
+	}
+	return nil
+}
+
+// SortStableContext is like SortStable, but checks ctx periodically while
+// sorting. When ctx is done, it returns ctx.Err() and leaves list as a
+// permutation of the input, which is not sorted.
+// It needs O(n) size extra memory.
+func SortStableContext[E cmp.Ordered](ctx context.Context, list []E) error {
+	done := ctx.Done()
+	if done == nil {
+		SortStable(list)
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if !sortStableCancel(list, done) {
+		return ctx.Err()
+	}
+	return nil
+}
+
+// The general version of SortContext.
+func (od *Order[E]) SortContext(ctx context.Context, list []E) error {
+	return od.sortContext(ctx, list, false)
+}
+
+// The general version of SortStableContext.
+func (od *Order[E]) SortStableContext(ctx context.Context, list []E) error {
+	return od.sortContext(ctx, list, true)
+}
+
+// sortContext sorts list like SortWithOption without inplace flag, and checks
+// ctx periodically.
+func (od *Order[E]) sortContext(ctx context.Context, list []E, stable bool) error {
+	done := ctx.Done()
+	if done == nil {
+		od.SortWithOption(list, stable, false)
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if len(list) < 2 {
+		return nil
+	}
+	var ok bool
+	switch od.strategy(list, stable, false, nil) {
+	case StrategyIntroSort:
+		switch od.kind() {
+		case kindLess:
+			ok = lessFunc[E](od.Less).sortFastCancel(list, done)
+		case kindRefLess:
+			ok = refLessFunc[E](od.RefLess).sortFastCancel(list, done)
+		case kindCompare:
+			ok = compareFunc[E](od.Compare).sortFastCancel(list, done)
+		default:
+			ok = refCompareFunc[E](od.RefCompare).sortFastCancel(list, done)
+		}
+	case StrategyMergeSort:
+		switch od.kind() {
+		case kindLess:
+			ok = lessFunc[E](od.Less).sortStableCancel(list, done)
+		case kindRefLess:
+			ok = refLessFunc[E](od.RefLess).sortStableCancel(list, done)
+		case kindCompare:
+			ok = compareFunc[E](od.Compare).sortStableCancel(list, done)
+		default:
+			ok = refCompareFunc[E](od.RefCompare).sortStableCancel(list, done)
+		}
+	default:
+		// list is untouched until pointers are sorted.
+		ref := refsOf(list)
+		if ok = od.sortRefs(ref, stable, done); ok {
+			reorder(list, ref)
+		}
+	}
+	if !ok {
+		return ctx.Err()
+	}
+	return nil
+}
@@ -0,0 +1,234 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slices
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// checkSortContext checks the result of a context-aware sort: sorted when err
+// is nil, otherwise a permutation of the input.
+func checkSortContext(t *testing.T, name string, data, sorted []int, err error) {
+	t.Helper()
+	if err == nil {
+		if !Equal(data, sorted) {
+			t.Errorf("%s: didn't sort", name)
+		}
+		return
+	}
+	if err != context.Canceled {
+		t.Errorf("%s: unexpected error %v", name, err)
+	}
+	data = Clone(data)
+	Sort(data)
+	if !Equal(data, sorted) {
+		t.Errorf("%s: not a permutation after cancellation", name)
+	}
+}
+
+func TestSortContext(t *testing.T) {
+	n := 1 << 20
+	if testing.Short() {
+		n = 1 << 18
+	}
+	data := make([]int, n)
+	for i := range data {
+		data[i] = rand.Intn(n)
+	}
+	sorted := Clone(data)
+	Sort(sorted)
+
+	list := Clone(data)
+	if err := SortContext(context.Background(), list); err != nil || !Equal(list, sorted) {
+		t.Errorf("SortContext: didn't sort with background context, err %v", err)
+	}
+	list = Clone(data)
+	if err := SortStableContext(context.Background(), list); err != nil || !Equal(list, sorted) {
+		t.Errorf("SortStableContext: didn't sort with background context, err %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	list = Clone(data)
+	if err := SortContext(ctx, list); err != context.Canceled || !Equal(list, data) {
+		t.Errorf("SortContext: touched list with cancelled context, err %v", err)
+	}
+	if err := SortStableContext(ctx, list); err != context.Canceled || !Equal(list, data) {
+		t.Errorf("SortStableContext: touched list with cancelled context, err %v", err)
+	}
+
+	// Cancel at some time, whether sorting has finished or not.
+	for _, delay := range []time.Duration{time.Millisecond, 10 * time.Millisecond} {
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(delay, cancel)
+		list = Clone(data)
+		checkSortContext(t, "SortContext", list, sorted, SortContext(ctx, list))
+
+		ctx, cancel = context.WithCancel(context.Background())
+		time.AfterFunc(delay, cancel)
+		list = Clone(data)
+		checkSortContext(t, "SortStableContext", list, sorted, SortStableContext(ctx, list))
+	}
+}
+
+func TestOrderSortContext(t *testing.T) {
+	n := 1 << 20
+	if testing.Short() {
+		n = 1 << 18
+	}
+	data := make([]intPair, n)
+	for i := range data {
+		data[i] = intPair{rand.Intn(n / 4), i}
+	}
+
+	// Cancel after a number of comparisons, in different phases of sorting.
+	for _, limit := range []int{1000, n, n * 10, n * 16, n * 18} {
+		for _, stable := range []bool{false, true} {
+			ctx, cancel := context.WithCancel(context.Background())
+			ncmp := 0
+			od := Order[intPair]{
+				Less: func(x, y intPair) bool {
+					if ncmp++; ncmp == limit {
+						cancel()
+					}
+					return x.a < y.a
+				}}
+			list := Clone(data)
+			var err error
+			if stable {
+				err = od.SortStableContext(ctx, list)
+			} else {
+				err = od.SortContext(ctx, list)
+			}
+			cancel()
+
+			if err == nil {
+				if limit == 1000 {
+					t.Errorf("limit %d (stable=%v): finished after cancellation", limit, stable)
+				}
+				if !intPairOrder.IsSorted(list) ||
+					(stable && !intPairs(list).inOrder()) {
+					t.Errorf("limit %d (stable=%v): didn't sort", limit, stable)
+				}
+				continue
+			}
+			if err != context.Canceled {
+				t.Errorf("limit %d (stable=%v): unexpected error %v", limit, stable, err)
+			}
+			seen := make([]bool, n)
+			for _, v := range list {
+				if seen[v.b] || data[v.b] != v {
+					t.Fatalf("limit %d (stable=%v): not a permutation after cancellation", limit, stable)
+				}
+				seen[v.b] = true
+			}
+		}
+	}
+	// Natural runs are detected.
+	sorted := Clone(data)
+	intPairOrder.SortStable(sorted)
+	ncmp := 0
+	od := Order[intPair]{
+		Less: func(x, y intPair) bool {
+			ncmp++
+			return x.a < y.a
+		}}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := od.SortStableContext(ctx, sorted); err != nil || ncmp != n-1 {
+		t.Errorf("SortStableContext took %d comparisons on sorted list, err %v", ncmp, err)
+	}
+}
+
+func TestOrderSortContextByRef(t *testing.T) {
+	n := cancelChunk * 2
+	data := make([]bigObject, n)
+	for i := range data {
+		data[i].val = rand.Intn(n)
+	}
+	// Sort by pointers even if they don't fit in cache.
+	defer func(available int) {
+		cacheInfo.available = available
+	}(cacheInfo.available)
+	cacheInfo.available = math.MaxInt
+
+	for _, stable := range []bool{false, true} {
+		ctx, cancel := context.WithCancel(context.Background())
+		ncmp, limit := 0, n*4
+		od := Order[bigObject]{
+			RefLess: func(x, y *bigObject) bool {
+				if ncmp++; ncmp == limit {
+					cancel()
+				}
+				return x.val < y.val
+			}}
+		list := Clone(data)
+		var err error
+		if stable {
+			err = od.SortStableContext(ctx, list)
+		} else {
+			err = od.SortContext(ctx, list)
+		}
+		if err != context.Canceled || !Equal(list, data) {
+			t.Errorf("stable=%v: list was touched after cancellation, err %v", stable, err)
+		}
+
+		ctx, cancel = context.WithCancel(context.Background())
+		limit = -1
+		if stable {
+			err = od.SortStableContext(ctx, list)
+		} else {
+			err = od.SortContext(ctx, list)
+		}
+		cancel()
+		if err != nil || !od.IsSorted(list) {
+			t.Errorf("stable=%v: didn't sort, err %v", stable, err)
+		}
+	}
+}
+
+func TestMergeCancel(t *testing.T) {
+	n := cancelChunk
+	for _, border := range []int{n, n * 3} {
+		// Interleaved runs, so merging takes all the way.
+		data := make([]int, n*4)
+		for i := 0; i < border; i++ {
+			data[i] = i * 2
+		}
+		for i := border; i < len(data); i++ {
+			data[i] = (i-border)*2 + 1
+		}
+		sorted := Clone(data)
+		Sort(sorted)
+		buf := make([]int, len(data)/2)
+
+		list := Clone(data)
+		if !mergeCancel(list, border, buf, nil) || !Equal(list, sorted) {
+			t.Errorf("border=%d: didn't merge", border)
+		}
+
+		// Cancel in the middle of the first chunk.
+		done := make(chan struct{})
+		count := 0
+		less := func(a, b int) bool {
+			if count++; count == n/2 {
+				close(done)
+			}
+			return a < b
+		}
+		list = Clone(data)
+		if lessFunc[int](less).mergeCancel(list, border, buf, done) {
+			t.Errorf("border=%d: didn't cancel", border)
+		}
+		Sort(list)
+		if !Equal(list, sorted) {
+			t.Errorf("border=%d: not a permutation after cancellation", border)
+		}
+	}
+}
@@ -0,0 +1,286 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slices
+
+import (
+	"bufio"
+	"cmp"
+	"errors"
+	"io"
+	"os"
+	"unsafe"
+)
+
+// Codec encodes elements into temporary files and decodes them back for
+// external sorting. The reader passed to Decode implements io.ByteReader.
+type Codec[E any] interface {
+	Encode(w io.Writer, elem *E) error
+	Decode(r io.Reader, elem *E) error
+}
+
+// Sorter sorts data larger than memory. Elements are accepted by Push in
+// chunks, sorted into runs in memory, and spilled into temporary files.
+// After Sort, the merged result can be read by Next.
+// Close should be called to remove temporary files.
+type Sorter[E any] struct {
+	// TempDir is the directory for temporary files.
+	// The default directory for temporary files is used when it is empty.
+	TempDir string
+	// MemoryLimit is the maximum bytes of elements held in memory.
+	// The default value is 1/16 of the physical memory.
+	MemoryLimit int
+	// Size returns the bytes of memory an element takes, including the data
+	// it refers to. Only the element itself is counted when it is nil, which
+	// is much less than the truth for strings, slices and pointers.
+	Size func(elem *E) int
+
+	codec  Codec[E]
+	sort   func([]E)
+	less   func(a, b *E) bool
+	buf    []E
+	used   int
+	runs   []sortedRun
+	merger *merger[E]
+	err    error
+}
+
+type sortedRun struct {
+	file  *os.File
+	size  int
+	level int // how many times elements in it have been merged
+}
+
+// Runs are merged at most maxMergeWays at a time.
+const maxMergeWays = 64
+
+var errSorterState = errors.New("slices: Sorter is used in wrong state")
+
+// NewSorter creates a Sorter for any ordered type.
+func NewSorter[E cmp.Ordered](codec Codec[E]) *Sorter[E] {
+	return &Sorter[E]{
+		MemoryLimit: memInfo.total / 16,
+		codec:       codec,
+		sort:        Sort[E],
+		less: func(a, b *E) bool {
+			return cmp.Less(*a, *b)
+		},
+	}
+}
+
+// The general version of NewSorter.
+// The Sorter created is stable, that keeps the original order of
+// equal elements.
+func (od *Order[E]) NewSorter(codec Codec[E]) *Sorter[E] {
+	return &Sorter[E]{
+		MemoryLimit: memInfo.total / 16,
+		codec:       codec,
+		sort:        od.SortStable,
+		less:        od.derive().RefLess,
+	}
+}
+
+// fit returns how many elements in list can be added into buffer, and
+// whether buffer becomes full then.
+func (s *Sorter[E]) fit(list []E) (int, bool) {
+	if s.Size == nil {
+		var elem E
+		limit := max(s.MemoryLimit/max(int(unsafe.Sizeof(elem)), 1), 1)
+		n := min(limit-len(s.buf), len(list))
+		return n, len(s.buf)+n >= limit
+	}
+	n := 0
+	for n < len(list) && (n == 0 || s.used < s.MemoryLimit) {
+		s.used += s.Size(&list[n])
+		n++
+	}
+	return n, s.used >= s.MemoryLimit
+}
+
+// Push adds elements into the Sorter. It may spill sorted runs into
+// temporary files when the memory limit is reached.
+func (s *Sorter[E]) Push(list ...E) error {
+	if s.err != nil {
+		return s.err
+	}
+	if s.merger != nil {
+		return errSorterState
+	}
+	for len(list) != 0 {
+		n, full := s.fit(list)
+		s.buf = append(s.buf, list[:n]...)
+		list = list[n:]
+		if full {
+			if s.err = s.spill(); s.err != nil {
+				return s.err
+			}
+		}
+	}
+	return nil
+}
+
+// spill sorts elements in buffer and writes them into a temporary file.
+func (s *Sorter[E]) spill() error {
+	s.sort(s.buf)
+	file, err := os.CreateTemp(s.TempDir, "sort-run-*")
+	if err != nil {
+		return err
+	}
+	s.runs = append(s.runs, sortedRun{file: file, size: len(s.buf)})
+	w := bufio.NewWriter(file)
+	for i := 0; i < len(s.buf); i++ {
+		if err := s.codec.Encode(w, &s.buf[i]); err != nil {
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	s.buf, s.used = s.buf[:0], 0
+	// Runs of the same level are merged when there are enough of them, like
+	// carrying in a counter of base maxMergeWays. So elements are rewritten
+	// only a logarithmic number of times.
+	for n := len(s.runs); n >= maxMergeWays &&
+		s.runs[n-maxMergeWays].level == s.runs[n-1].level; n = len(s.runs) {
+		if err := s.compact(maxMergeWays); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// compact merges the last k runs into one.
+func (s *Sorter[E]) compact(k int) error {
+	runs := s.runs[len(s.runs)-k:]
+	file, err := os.CreateTemp(s.TempDir, "sort-run-*")
+	if err != nil {
+		return err
+	}
+	merged := sortedRun{file: file, level: runs[0].level + 1}
+	sources := make([]func() (E, bool), k)
+	for i, run := range runs {
+		if sources[i], err = s.readRun(run); err != nil {
+			file.Close()
+			os.Remove(file.Name())
+			return err
+		}
+		merged.size += run.size
+	}
+	m := newMerger(s.less, sources)
+	w := bufio.NewWriter(file)
+	for v, ok := m.next(); ok && s.err == nil; v, ok = m.next() {
+		s.err = s.codec.Encode(w, &v)
+	}
+	if s.err == nil {
+		s.err = w.Flush()
+	}
+	for _, run := range runs {
+		run.file.Close()
+		os.Remove(run.file.Name())
+	}
+	s.runs = append(s.runs[:len(s.runs)-k], merged)
+	return s.err
+}
+
+// readRun returns a function which yields elements in a run one by one.
+func (s *Sorter[E]) readRun(run sortedRun) (func() (E, bool), error) {
+	if _, err := run.file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	r := bufio.NewReader(run.file)
+	remain := run.size
+	return func() (E, bool) {
+		var elem E
+		if remain == 0 || s.err != nil {
+			return elem, false
+		}
+		if err := s.codec.Decode(r, &elem); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			s.err = err
+			return elem, false
+		}
+		remain--
+		return elem, true
+	}, nil
+}
+
+// Sort finishes accepting elements, and prepares to output them in order.
+func (s *Sorter[E]) Sort() error {
+	if s.err != nil {
+		return s.err
+	}
+	if s.merger != nil {
+		return errSorterState
+	}
+	s.sort(s.buf)
+	// Merge the smallest runs first, if too many are left for one pass.
+	for len(s.runs) >= maxMergeWays {
+		if s.err = s.compact(min(len(s.runs)-maxMergeWays+2, maxMergeWays)); s.err != nil {
+			return s.err
+		}
+	}
+	sources := make([]func() (E, bool), 0, len(s.runs)+1)
+	for _, run := range s.runs {
+		src, err := s.readRun(run)
+		if err != nil {
+			s.err = err
+			return err
+		}
+		sources = append(sources, src)
+	}
+	// Elements in buffer are the last pushed ones.
+	buf := s.buf
+	sources = append(sources, func() (elem E, ok bool) {
+		if len(buf) == 0 {
+			return elem, false
+		}
+		elem, buf = buf[0], buf[1:]
+		return elem, true
+	})
+	s.merger = newMerger(s.less, sources)
+	return s.err
+}
+
+// Next returns the next element in order after Sort.
+// It returns false when all elements are consumed or an error occurs,
+// use Err to tell them apart.
+func (s *Sorter[E]) Next() (E, bool) {
+	if s.merger == nil || s.err != nil {
+		var elem E
+		return elem, false
+	}
+	elem, ok := s.merger.next()
+	if s.err != nil {
+		return elem, false
+	}
+	return elem, ok
+}
+
+// Err returns the first error encountered.
+func (s *Sorter[E]) Err() error {
+	return s.err
+}
+
+// Close releases memory and removes temporary files.
+// The Sorter can not be used anymore.
+func (s *Sorter[E]) Close() error {
+	var err error
+	for _, run := range s.runs {
+		if e := run.file.Close(); e != nil && err == nil {
+			err = e
+		}
+		if e := os.Remove(run.file.Name()); e != nil && err == nil {
+			err = e
+		}
+	}
+	s.runs = nil
+	s.buf = nil
+	s.merger = nil
+	if s.err == nil {
+		s.err = errSorterState
+	}
+	return err
+}
@@ -0,0 +1,234 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slices
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/rand"
+	"os"
+	"testing"
+)
+
+type int64Codec struct{}
+
+func (int64Codec) Encode(w io.Writer, elem *int64) error {
+	return binary.Write(w, binary.LittleEndian, *elem)
+}
+
+func (int64Codec) Decode(r io.Reader, elem *int64) error {
+	return binary.Read(r, binary.LittleEndian, elem)
+}
+
+type intPairCodec struct{}
+
+func (intPairCodec) Encode(w io.Writer, elem *intPair) error {
+	return binary.Write(w, binary.LittleEndian, [2]int64{int64(elem.a), int64(elem.b)})
+}
+
+func (intPairCodec) Decode(r io.Reader, elem *intPair) error {
+	var v [2]int64
+	if err := binary.Read(r, binary.LittleEndian, &v); err != nil {
+		return err
+	}
+	elem.a, elem.b = int(v[0]), int(v[1])
+	return nil
+}
+
+func externalTestSize() int {
+	if testing.Short() {
+		return 10000
+	}
+	return 200000
+}
+
+func checkTempDirEmpty(t *testing.T, dir string) {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("%d temporary files left", len(entries))
+	}
+}
+
+func TestSorter(t *testing.T) {
+	n := externalTestSize()
+	data := make([]int64, n)
+	for i := 0; i < n; i++ {
+		data[i] = rand.Int63n(int64(n)) - int64(n/2)
+	}
+	want := Clone(data)
+	Sort(want)
+
+	// Small limits produce more runs than maxMergeWays.
+	for _, limit := range []int{n / 100 * 8, n / 10 * 8, n * 16} {
+		dir := t.TempDir()
+		s := NewSorter[int64](int64Codec{})
+		s.TempDir = dir
+		s.MemoryLimit = limit
+		for i := 0; i < n; i += 1000 {
+			if err := s.Push(data[i:min(i+1000, n)]...); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := s.Sort(); err != nil {
+			t.Fatal(err)
+		}
+		got := make([]int64, 0, n)
+		for v, ok := s.Next(); ok; v, ok = s.Next() {
+			got = append(got, v)
+		}
+		if err := s.Err(); err != nil {
+			t.Fatal(err)
+		}
+		if !Equal(got, want) {
+			t.Errorf("Sorter mismatch with Sort under memory limit %d", limit)
+		}
+		if err := s.Close(); err != nil {
+			t.Fatal(err)
+		}
+		checkTempDirEmpty(t, dir)
+		if err := s.Push(0); err == nil {
+			t.Errorf("Push succeeded after Close")
+		}
+	}
+}
+
+func TestSorterStability(t *testing.T) {
+	n, m := externalTestSize(), 1000
+	data := make(intPairs, n)
+	for i := 0; i < n; i++ {
+		data[i].a = rand.Intn(m)
+	}
+	data.initB()
+
+	dir := t.TempDir()
+	s := intPairOrder.NewSorter(intPairCodec{})
+	s.TempDir = dir
+	s.MemoryLimit = n / 100 * 16
+	if err := s.Push(data...); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Sort(); err != nil {
+		t.Fatal(err)
+	}
+	got := make(intPairs, 0, n)
+	for v, ok := s.Next(); ok; v, ok = s.Next() {
+		got = append(got, v)
+	}
+	if err := s.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != n || !intPairOrder.IsSorted(got) {
+		t.Errorf("Sorter didn't sort %d ints", n)
+	}
+	if !got.inOrder() {
+		t.Errorf("Sorter wasn't stable on %d ints", n)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	checkTempDirEmpty(t, dir)
+}
+
+type countingCodec struct {
+	int64Codec
+	encoded int
+}
+
+func (c *countingCodec) Encode(w io.Writer, elem *int64) error {
+	c.encoded++
+	return c.int64Codec.Encode(w, elem)
+}
+
+func TestSorterManyRuns(t *testing.T) {
+	// 40 runs of level 1 and 30 runs of level 0.
+	n := maxMergeWays*40 + 30
+	data := make([]int64, n)
+	for i := 0; i < n; i++ {
+		data[i] = rand.Int63n(int64(n))
+	}
+	want := Clone(data)
+	Sort(want)
+
+	dir := t.TempDir()
+	codec := &countingCodec{}
+	s := NewSorter[int64](codec)
+	s.TempDir = dir
+	s.MemoryLimit = 100
+	s.Size = func(*int64) int { return 100 }
+	if err := s.Push(data...); err != nil {
+		t.Fatal(err)
+	}
+	if len(s.runs) != 70 || len(s.buf) != 0 {
+		t.Errorf("got %d runs and %d buffered elements", len(s.runs), len(s.buf))
+	}
+	if err := s.Sort(); err != nil {
+		t.Fatal(err)
+	}
+	got := make([]int64, 0, n)
+	for v, ok := s.Next(); ok; v, ok = s.Next() {
+		got = append(got, v)
+	}
+	if err := s.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if !Equal(got, want) {
+		t.Errorf("Sorter mismatch with Sort on %d runs", n)
+	}
+	if codec.encoded > n*2+maxMergeWays {
+		t.Errorf("%d elements encoded %d times", n, codec.encoded)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	checkTempDirEmpty(t, dir)
+}
+
+type failingCodec struct {
+	int64Codec
+	count int
+}
+
+var errDecode = errors.New("decode failure")
+
+func (c *failingCodec) Decode(r io.Reader, elem *int64) error {
+	if c.count++; c.count > 1000 {
+		return errDecode
+	}
+	return c.int64Codec.Decode(r, elem)
+}
+
+func TestSorterError(t *testing.T) {
+	dir := t.TempDir()
+	s := NewSorter[int64](&failingCodec{})
+	s.TempDir = dir
+	s.MemoryLimit = 80
+	for i := 0; i < 1000; i++ {
+		if err := s.Push(rand.Int63()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.Sort(); err != nil {
+		t.Fatal(err)
+	}
+	cnt := 0
+	for _, ok := s.Next(); ok; _, ok = s.Next() {
+		cnt++
+	}
+	if cnt >= 1000 {
+		t.Errorf("Sorter yielded all elements despite decode failure")
+	}
+	if err := s.Err(); err != errDecode {
+		t.Errorf("Err got %v, want %v", err, errDecode)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	checkTempDirEmpty(t, dir)
+}
@@ -5,12 +5,15 @@
 //go:build ignore
 
 // This program is run via "go generate" (via a directive in sort_ordered.go)
-// to generate zfunc_a.go & zfunc_b.go.
+// to generate zfunc_a.go ... zfunc_h.go. In zfunc_d.go, zfunc_e.go and
+// zfunc_g.go, the functions in sort_compare.go take the place of the ones with
+// same names.
 
 package main
 
 import (
 	"bytes"
+	"fmt"
 	"go/ast"
 	"go/format"
 	"go/parser"
@@ -18,45 +21,32 @@ import (
 	"log"
 	"os"
 	"regexp"
+	"strings"
 )
 
 var hackedFuncs = make(map[string]bool)
 
 func main() {
 	fset := token.NewFileSet()
-	af, err := parser.ParseFile(fset, "sort_ordered.go", nil, 0)
-	if err != nil {
-		log.Fatal(err)
-	}
-	af.Doc = nil
-	af.Imports = nil
-	af.Comments = nil
+	decls := parseTemplate(fset, "sort_ordered.go")
+	tpl := dumpTemplate(fset, decls)
 
-	var newDecl []ast.Decl
-	for _, d := range af.Decls {
-		fd, ok := d.(*ast.FuncDecl)
-		if !ok || fd.Recv != nil || fd.Name.IsExported() ||
-			fd.Type.TypeParams == nil || len(fd.Type.TypeParams.List) != 1 {
-			continue
+	// In three-way versions, overrides replace the functions with same names,
+	// or are appended.
+	decls3 := append([]ast.Decl(nil), decls...)
+	for _, o := range parseTemplate(fset, "sort_compare.go") {
+		name := o.(*ast.FuncDecl).Name.Name
+		i := 0
+		for i < len(decls3) && decls3[i].(*ast.FuncDecl).Name.Name != name {
+			i++
 		}
-		field := fd.Type.TypeParams.List[0]
-		if expr, ok := field.Type.(*ast.SelectorExpr); !ok ||
-			expr.Sel.Name != "Ordered" ||
-			len(field.Names) != 1 || field.Names[0].Name != "E" {
-			continue
+		if i < len(decls3) {
+			decls3[i] = o
+		} else {
+			decls3 = append(decls3, o)
 		}
-		hackedFuncs[fd.Name.Name] = true
-		fd.Type.TypeParams = nil
-		newDecl = append(newDecl, fd)
-	}
-	af.Decls = newDecl
-	ast.Walk(visitFunc(rewriteCalls), af)
-
-	var out bytes.Buffer
-	if err := format.Node(&out, fset, af); err != nil {
-		log.Fatalf("format.Node: %v", err)
 	}
-	tpl := out.Bytes()
+	tpl3 := dumpTemplate(fset, decls3)
 
 	funcPtn := regexp.MustCompile(`\nfunc `)
 	lessPtn := regexp.MustCompile(`cmp\.Less\([^\),]+,[^\),]+\)`)
@@ -71,7 +61,7 @@ func main() {
 		}
 		return out
 	})
-	dumpOrDie("zfunc_a.go", src)
+	dumpOrDie("zfunc_a.go", "sort_ordered.go", src)
 
 	src = funcPtn.ReplaceAll(tpl, []byte("\nfunc (lt refLessFunc[E]) "))
 	src = lessPtn.ReplaceAllFunc(src, func(origin []byte) []byte {
@@ -90,7 +80,288 @@ func main() {
 		}
 		return out
 	})
-	dumpOrDie("zfunc_b.go", src)
+	dumpOrDie("zfunc_b.go", "sort_ordered.go", src)
+
+	elemPtn := regexp.MustCompile(`\bE\b`)
+	lessArgsPtn := regexp.MustCompile(`cmp\.Less\(([^\),]+),\s*([^\),]+)\)`)
+
+	src = funcPtn.ReplaceAll(tpl, []byte("\nfunc (lt keyedOrder[K]) "))
+	src = elemPtn.ReplaceAll(src, []byte("keyed[K]"))
+	src = lessArgsPtn.ReplaceAll(src, []byte("cmp.Less($1.key, $2.key)"))
+	src = bytes.Replace(src, []byte("package slices\n"),
+		[]byte("package slices\n\nimport \"cmp\"\n"), 1)
+	dumpOrDie("zfunc_c.go", "sort_ordered.go", src)
+
+	// Block partition is only enabled on amd64, the keyed version is used
+	// by sortByKey.
+	var bqs []ast.Decl
+	for _, d := range parseTemplate(fset, "bqs_enabled.go") {
+		switch d.(*ast.FuncDecl).Name.Name {
+		case "blockIntroSort", "blockPartition", "compGE":
+			bqs = append(bqs, d)
+		}
+	}
+	// Raw comparisons between elements there are replaced with cmp.Less,
+	// because keys are not cleared of NaNs.
+	rawCmpPtn := regexp.MustCompile(`(\b[ab]\b|list\[\w+\]|pivot) ([<>]) (\b[ab]\b|list\[\w+\]|pivot)`)
+	src = funcPtn.ReplaceAll(dumpTemplate(fset, bqs), []byte("\nfunc (lt keyedOrder[K]) "))
+	src = elemPtn.ReplaceAll(src, []byte("keyed[K]"))
+	src = rawCmpPtn.ReplaceAllFunc(src, func(origin []byte) []byte {
+		m := rawCmpPtn.FindSubmatch(origin)
+		if string(m[2]) == ">" {
+			m[1], m[3] = m[3], m[1]
+		}
+		return []byte("cmp.Less(" + string(m[1]) + ".key, " + string(m[3]) + ".key)")
+	})
+	src = bytes.Replace(src, []byte("package slices\n"),
+		[]byte("package slices\n\nimport \"cmp\"\n"), 1)
+	dumpOrDie("zfunc_c_amd64.go", "bqs_enabled.go", src)
+
+	notLessPtn := regexp.MustCompile(`!cmp\.Less\(([^\),]+),\s*([^\),]+)\)`)
+	comparePtn := regexp.MustCompile(`cmp\.Compare\(([^\),]+),\s*([^\),]+)\)`)
+
+	src = funcPtn.ReplaceAll(tpl3, []byte("\nfunc (lt compareFunc[E]) "))
+	src = notLessPtn.ReplaceAll(src, []byte("lt($1, $2) >= 0"))
+	src = lessArgsPtn.ReplaceAll(src, []byte("lt($1, $2) < 0"))
+	src = comparePtn.ReplaceAll(src, []byte("lt($1, $2)"))
+	dumpOrDie("zfunc_d.go", "sort_ordered.go", src)
+
+	src = funcPtn.ReplaceAll(tpl3, []byte("\nfunc (lt refCompareFunc[E]) "))
+	src = notLessPtn.ReplaceAll(src, []byte("lt(&$1, &$2) >= 0"))
+	src = lessArgsPtn.ReplaceAll(src, []byte("lt(&$1, &$2) < 0"))
+	src = comparePtn.ReplaceAll(src, []byte("lt(&$1, &$2)"))
+	dumpOrDie("zfunc_e.go", "sort_ordered.go", src)
+
+	observePtn := regexp.MustCompile(`\bobserve\[E\]\(`)
+
+	src = funcPtn.ReplaceAll(tpl, []byte("\nfunc (lt *statsOrder[E]) "))
+	src = lessArgsPtn.ReplaceAll(src, []byte("lt.less(&$1, &$2)"))
+	src = observePtn.ReplaceAll(src, []byte("lt.observe("))
+	dumpOrDie("zfunc_f.go", "sort_ordered.go", src)
+
+	src = funcPtn.ReplaceAll(tpl3, []byte("\nfunc (lt *statsCompareOrder[E]) "))
+	src = notLessPtn.ReplaceAll(src, []byte("lt.compare(&$1, &$2) >= 0"))
+	src = lessArgsPtn.ReplaceAll(src, []byte("lt.compare(&$1, &$2) < 0"))
+	src = comparePtn.ReplaceAll(src, []byte("lt.compare(&$1, &$2)"))
+	src = observePtn.ReplaceAll(src, []byte("lt.observe("))
+	dumpOrDie("zfunc_g.go", "sort_ordered.go", src)
+
+	// The columns version moves elements only by swapping, so other columns
+	// can follow. Functions are parsed again, because they are rewritten.
+	cols := reachable(parseTemplate(fset, "sort_ordered.go"),
+		"sortFast", "extendRun", "mergeInPlace")
+	swapOnly(fset, cols)
+	src = funcPtn.ReplaceAll(dumpTemplate(fset, cols), []byte("\nfunc (lt columns[K]) "))
+	src = elemPtn.ReplaceAll(src, []byte("K"))
+	src = bytes.Replace(src, []byte("package slices\n"),
+		[]byte("package slices\n\nimport \"cmp\"\n"), 1)
+	dumpOrDie("zfunc_h.go", "sort_ordered.go", src)
+}
+
+// reachable picks roots and the functions called by them from decls.
+func reachable(decls []ast.Decl, roots ...string) []ast.Decl {
+	funcs := make(map[string]*ast.FuncDecl)
+	for _, d := range decls {
+		fd := d.(*ast.FuncDecl)
+		funcs[fd.Name.Name] = fd
+	}
+	picked := make(map[string]bool)
+	var pick func(name string)
+	pick = func(name string) {
+		fd := funcs[name]
+		if fd == nil || picked[name] {
+			return
+		}
+		picked[name] = true
+		ast.Inspect(fd.Body, func(n ast.Node) bool {
+			if ce, ok := n.(*ast.CallExpr); ok {
+				if ident, ok := ce.Fun.(*ast.Ident); ok {
+					pick(ident.Name)
+				}
+			}
+			return true
+		})
+	}
+	for _, name := range roots {
+		pick(name)
+	}
+	var out []ast.Decl
+	for _, d := range decls {
+		if picked[d.(*ast.FuncDecl).Name.Name] {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// swapOnly rewrites writes to list in decls into calls of lt.swap.
+// Exchanges like list[a], list[b] = list[b], list[a] are done by swaps.
+// Others should move elements into the vacant slot, which is left by the
+// element saved in a variable: list[a] = list[b] becomes a swap, which leaves
+// the slot at b, and list[a] = saved is dropped, since the saved element is
+// already there. Reverse and rotateLeft are replaced with the methods, and
+// other writes are rejected.
+func swapOnly(fset *token.FileSet, decls []ast.Decl) {
+	for _, d := range decls {
+		ast.Inspect(d, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.BlockStmt:
+				n.List = swapStmts(fset, n.List)
+			case *ast.CaseClause:
+				n.Body = swapStmts(fset, n.Body)
+			case *ast.AssignStmt:
+				for _, x := range n.Lhs {
+					if _, ok := x.(*ast.IndexExpr); ok {
+						log.Fatalf("%s: unexpected write", fset.Position(x.Pos()))
+					}
+				}
+			case *ast.CallExpr:
+				if ident, ok := n.Fun.(*ast.Ident); ok {
+					switch ident.Name {
+					case "Reverse", "reverse":
+						ident.Name = "lt.reverse"
+					case "rotateLeft":
+						ident.Name = "lt.rotateLeft"
+					case "copy":
+						log.Fatalf("%s: unexpected copy", fset.Position(n.Pos()))
+					}
+				}
+			}
+			return true
+		})
+	}
+}
+
+func swapStmts(fset *token.FileSet, stmts []ast.Stmt) []ast.Stmt {
+	var out []ast.Stmt
+	for _, s := range stmts {
+		as, ok := s.(*ast.AssignStmt)
+		if !ok || as.Tok != token.ASSIGN || !isElem(as.Lhs[0]) {
+			out = append(out, s)
+			continue
+		}
+		lhs := make([]string, len(as.Lhs))
+		rhs := make([]string, len(as.Rhs))
+		for i := range as.Lhs {
+			if !isElem(as.Lhs[i]) {
+				log.Fatalf("%s: unexpected write", fset.Position(as.Pos()))
+			}
+			lhs[i] = exprString(fset, as.Lhs[i])
+			rhs[i] = exprString(fset, as.Rhs[i])
+		}
+		index := func(i int) ast.Expr {
+			return as.Lhs[i].(*ast.IndexExpr).Index
+		}
+		if isPermutation(lhs, rhs) {
+			// Put the wanted element in place one by one.
+			for i := range lhs {
+				j := i
+				for lhs[j] != rhs[i] {
+					j++
+				}
+				if j != i {
+					out = append(out, swapStmt(index(i), index(j)))
+					lhs[i], lhs[j] = lhs[j], lhs[i]
+				}
+			}
+			continue
+		}
+		for i, x := range as.Rhs {
+			switch x := x.(type) {
+			case *ast.IndexExpr:
+				if !isElem(x) {
+					log.Fatalf("%s: unexpected move", fset.Position(x.Pos()))
+				}
+				out = append(out, swapStmt(index(i), x.Index))
+			case *ast.Ident:
+				// The saved element is already in the vacant slot.
+			default:
+				log.Fatalf("%s: unexpected move", fset.Position(x.Pos()))
+			}
+		}
+	}
+	return out
+}
+
+func isElem(x ast.Expr) bool {
+	ie, ok := x.(*ast.IndexExpr)
+	if !ok {
+		return false
+	}
+	ident, ok := ie.X.(*ast.Ident)
+	return ok && ident.Name == "list"
+}
+
+func isPermutation(a, b []string) bool {
+	count := make(map[string]int)
+	for i := range a {
+		count[a[i]]++
+		count[b[i]]--
+	}
+	for _, c := range count {
+		if c != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func swapStmt(i, j ast.Expr) ast.Stmt {
+	return &ast.ExprStmt{X: &ast.CallExpr{
+		Fun:  ast.NewIdent("lt.swap"),
+		Args: []ast.Expr{ast.NewIdent("list"), i, j},
+	}}
+}
+
+func exprString(fset *token.FileSet, x ast.Expr) string {
+	var out strings.Builder
+	if err := format.Node(&out, fset, x); err != nil {
+		log.Fatalf("format.Node: %v", err)
+	}
+	return out.String()
+}
+
+// parseTemplate picks the functions with single type parameter E cmp.Ordered
+// from filename, and removes the type parameter.
+func parseTemplate(fset *token.FileSet, filename string) []ast.Decl {
+	af, err := parser.ParseFile(fset, filename, nil, 0)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var decls []ast.Decl
+	for _, d := range af.Decls {
+		fd, ok := d.(*ast.FuncDecl)
+		if !ok || fd.Recv != nil || fd.Name.IsExported() ||
+			fd.Type.TypeParams == nil || len(fd.Type.TypeParams.List) != 1 {
+			continue
+		}
+		field := fd.Type.TypeParams.List[0]
+		if expr, ok := field.Type.(*ast.SelectorExpr); !ok ||
+			expr.Sel.Name != "Ordered" ||
+			len(field.Names) != 1 || field.Names[0].Name != "E" {
+			continue
+		}
+		hackedFuncs[fd.Name.Name] = true
+		fd.Type.TypeParams = nil
+		fd.Doc = nil
+		decls = append(decls, fd)
+	}
+	return decls
+}
+
+// dumpTemplate prints decls, with calls of the hacked functions turned into
+// method calls. Calls are renamed only once, so decls can be shared.
+func dumpTemplate(fset *token.FileSet, decls []ast.Decl) []byte {
+	af := &ast.File{Name: ast.NewIdent("slices"), Decls: decls}
+	for _, d := range decls {
+		ast.Walk(visitFunc(rewriteCalls), d)
+	}
+	var out bytes.Buffer
+	if err := format.Node(&out, fset, af); err != nil {
+		log.Fatalf("format.Node: %v", err)
+	}
+	// Decls from different files may be printed without blank line between.
+	return bytes.ReplaceAll(out.Bytes(), []byte("}\nfunc "), []byte("}\n\nfunc "))
 }
 
 type visitFunc func(ast.Node) ast.Visitor
@@ -108,7 +379,7 @@ func rewriteCalls(n ast.Node) ast.Visitor {
 	return visitFunc(rewriteCalls)
 }
 
-var header = `// Code generated from sort_ordered.go using genzfunc.go; DO NOT EDIT.
+var header = `// Code generated from %s using genzfunc.go; DO NOT EDIT.
 
 // Copyright 2023 The Go Authors. All rights reserved.
 // Use of this source code is governed by a BSD-style
@@ -116,7 +387,7 @@ var header = `// Code generated from sort_ordered.go using genzfunc.go; DO NOT E
 
 `
 
-func dumpOrDie(filename string, src []byte) {
+func dumpOrDie(filename, origin string, src []byte) {
 	src, err := format.Source(src)
 	if err != nil {
 		log.Fatalf("format.Source: %v on\n%s", err, src)
@@ -126,7 +397,7 @@ func dumpOrDie(filename string, src []byte) {
 		log.Fatal(err)
 	}
 	defer out.Close()
-	if _, err := out.WriteString(header); err != nil {
+	if _, err := fmt.Fprintf(out, header, origin); err != nil {
 		log.Fatal(err)
 	}
 	if _, err := out.Write(src); err != nil {
@@ -0,0 +1,267 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slices
+
+import (
+	"cmp"
+	"math/bits"
+)
+
+type siftFunc[E any] func(list []E, pos int, shift uint, ids, index []int) int
+
+// heapShift converts arity to shift, arity less than 2 means binary heap.
+func heapShift(arity int) uint {
+	if arity < 2 {
+		return 1
+	}
+	if arity&(arity-1) != 0 {
+		panic("slices.NewHeap: arity is not a power of 2")
+	}
+	return uint(bits.TrailingZeros(uint(arity)))
+}
+
+// Heap is a min-heap, the least element is on the top.
+// Use the reversed Order to make a max-heap.
+type Heap[E any] struct {
+	list  []E
+	shift uint
+	down  siftFunc[E]
+	up    siftFunc[E]
+}
+
+// NewHeap creates an empty Heap, whose nodes have arity children.
+// arity should be a power of 2, binary heap is used when it's less than 2.
+// 4-ary heap is often faster than binary heap for its shallower tree.
+func NewHeap[E cmp.Ordered](arity int) *Heap[E] {
+	return &Heap[E]{
+		shift: heapShift(arity),
+		down:  heapSiftDown[E],
+		up:    heapSiftUp[E],
+	}
+}
+
+// The general version of NewHeap.
+func (od *Order[E]) NewHeap(arity int) *Heap[E] {
+	h := &Heap[E]{shift: heapShift(arity)}
+	switch od.kind() {
+	case kindLess:
+		algo := lessFunc[E](od.Less)
+		h.down, h.up = algo.heapSiftDown, algo.heapSiftUp
+	case kindRefLess:
+		algo := refLessFunc[E](od.RefLess)
+		h.down, h.up = algo.heapSiftDown, algo.heapSiftUp
+	case kindCompare:
+		algo := compareFunc[E](od.Compare)
+		h.down, h.up = algo.heapSiftDown, algo.heapSiftUp
+	default:
+		algo := refCompareFunc[E](od.RefCompare)
+		h.down, h.up = algo.heapSiftDown, algo.heapSiftUp
+	}
+	return h
+}
+
+// Init builds the heap from list in O(n) time. Elements in the heap are
+// dropped. list is used as the storage of heap, so it should not be used
+// by the caller after that.
+func (h *Heap[E]) Init(list []E) {
+	h.list = list
+	for i := (len(list) - 2) >> h.shift; i >= 0; i-- {
+		h.down(list, i, h.shift, nil, nil)
+	}
+}
+
+// Len returns the number of elements in the heap.
+func (h *Heap[E]) Len() int {
+	return len(h.list)
+}
+
+// Elements returns elements in the heap in heap order. Call Fix after
+// modifying any of them.
+func (h *Heap[E]) Elements() []E {
+	return h.list
+}
+
+// Push adds an element into the heap.
+func (h *Heap[E]) Push(x E) {
+	h.list = append(h.list, x)
+	h.up(h.list, len(h.list)-1, h.shift, nil, nil)
+}
+
+// Peek returns the least element. It panics if the heap is empty.
+func (h *Heap[E]) Peek() E {
+	if len(h.list) == 0 {
+		panic("slices.Heap: empty heap")
+	}
+	return h.list[0]
+}
+
+// Pop removes and returns the least element. It panics if the heap is empty.
+func (h *Heap[E]) Pop() E {
+	if len(h.list) == 0 {
+		panic("slices.Heap: empty heap")
+	}
+	return h.Remove(0)
+}
+
+// Remove removes and returns the element at position i of Elements.
+func (h *Heap[E]) Remove(i int) E {
+	last := len(h.list) - 1
+	x := h.list[i]
+	h.list[i] = h.list[last]
+	var zero E
+	h.list[last] = zero
+	h.list = h.list[:last]
+	if i < last {
+		h.Fix(i)
+	}
+	return x
+}
+
+// Fix restores the heap after the element at position i of Elements has
+// been changed.
+func (h *Heap[E]) Fix(i int) {
+	if h.up(h.list, i, h.shift, nil, nil) == i {
+		h.down(h.list, i, h.shift, nil, nil)
+	}
+}
+
+// IndexedHeap is a min-heap of elements with ids, which are small
+// non-negative integers like vertex numbers in a graph. Elements can be
+// found and updated by id, that's the decrease-key operation in Dijkstra's
+// algorithm.
+type IndexedHeap[E any] struct {
+	list  []E
+	ids   []int // id of each element
+	index []int // position of each id, -1 when it's not in the heap
+	shift uint
+	down  siftFunc[E]
+	up    siftFunc[E]
+}
+
+// NewIndexedHeap creates an empty IndexedHeap, arity works like NewHeap.
+func NewIndexedHeap[E cmp.Ordered](arity int) *IndexedHeap[E] {
+	return &IndexedHeap[E]{
+		shift: heapShift(arity),
+		down:  heapSiftDown[E],
+		up:    heapSiftUp[E],
+	}
+}
+
+// The general version of NewIndexedHeap.
+func (od *Order[E]) NewIndexedHeap(arity int) *IndexedHeap[E] {
+	h := &IndexedHeap[E]{shift: heapShift(arity)}
+	switch od.kind() {
+	case kindLess:
+		algo := lessFunc[E](od.Less)
+		h.down, h.up = algo.heapSiftDown, algo.heapSiftUp
+	case kindRefLess:
+		algo := refLessFunc[E](od.RefLess)
+		h.down, h.up = algo.heapSiftDown, algo.heapSiftUp
+	case kindCompare:
+		algo := compareFunc[E](od.Compare)
+		h.down, h.up = algo.heapSiftDown, algo.heapSiftUp
+	default:
+		algo := refCompareFunc[E](od.RefCompare)
+		h.down, h.up = algo.heapSiftDown, algo.heapSiftUp
+	}
+	return h
+}
+
+// Len returns the number of elements in the heap.
+func (h *IndexedHeap[E]) Len() int {
+	return len(h.list)
+}
+
+// Contains reports whether id is in the heap.
+func (h *IndexedHeap[E]) Contains(id int) bool {
+	return id >= 0 && id < len(h.index) && h.index[id] >= 0
+}
+
+// Get returns the element with id, and whether it's in the heap.
+func (h *IndexedHeap[E]) Get(id int) (E, bool) {
+	if !h.Contains(id) {
+		var zero E
+		return zero, false
+	}
+	return h.list[h.index[id]], true
+}
+
+// Push adds an element with id, or updates it when id is in the heap already.
+// It panics if id is negative.
+func (h *IndexedHeap[E]) Push(id int, x E) {
+	if id < 0 {
+		panic("slices.IndexedHeap: negative id")
+	}
+	if h.Contains(id) {
+		h.Update(id, x)
+		return
+	}
+	for len(h.index) <= id {
+		h.index = append(h.index, -1)
+	}
+	h.list = append(h.list, x)
+	h.ids = append(h.ids, id)
+	h.index[id] = len(h.list) - 1
+	h.up(h.list, len(h.list)-1, h.shift, h.ids, h.index)
+}
+
+// Update changes the element with id, it's fine to increase or decrease it.
+// It panics if id is not in the heap.
+func (h *IndexedHeap[E]) Update(id int, x E) {
+	if !h.Contains(id) {
+		panic("slices.IndexedHeap: id not found")
+	}
+	i := h.index[id]
+	h.list[i] = x
+	h.fix(i)
+}
+
+// Peek returns the least element with its id. It panics if the heap is empty.
+func (h *IndexedHeap[E]) Peek() (int, E) {
+	if len(h.list) == 0 {
+		panic("slices.IndexedHeap: empty heap")
+	}
+	return h.ids[0], h.list[0]
+}
+
+// Pop removes and returns the least element with its id.
+// It panics if the heap is empty.
+func (h *IndexedHeap[E]) Pop() (int, E) {
+	if len(h.list) == 0 {
+		panic("slices.IndexedHeap: empty heap")
+	}
+	id := h.ids[0]
+	return id, h.remove(0)
+}
+
+// Remove removes and returns the element with id, and whether it's in the heap.
+func (h *IndexedHeap[E]) Remove(id int) (E, bool) {
+	if !h.Contains(id) {
+		var zero E
+		return zero, false
+	}
+	return h.remove(h.index[id]), true
+}
+
+func (h *IndexedHeap[E]) remove(i int) E {
+	last := len(h.list) - 1
+	x := h.list[i]
+	h.index[h.ids[i]] = -1
+	h.list[i], h.ids[i] = h.list[last], h.ids[last]
+	var zero E
+	h.list[last] = zero
+	h.list, h.ids = h.list[:last], h.ids[:last]
+	if i < last {
+		h.index[h.ids[i]] = i
+		h.fix(i)
+	}
+	return x
+}
+
+func (h *IndexedHeap[E]) fix(i int) {
+	if h.up(h.list, i, h.shift, h.ids, h.index) == i {
+		h.down(h.list, i, h.shift, h.ids, h.index)
+	}
+}
@@ -0,0 +1,208 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slices
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func checkHeap(t *testing.T, h *Heap[int], arity int) {
+	t.Helper()
+	list := h.Elements()
+	arity = max(arity, 2)
+	for i := 1; i < len(list); i++ {
+		if parent := (i - 1) / arity; list[i] < list[parent] {
+			t.Fatalf("arity %d: list[%d] = %d < list[%d] = %d",
+				arity, i, list[i], parent, list[parent])
+		}
+	}
+}
+
+func TestHeap(t *testing.T) {
+	n := 1000
+	for _, arity := range []int{0, 2, 4, 8} {
+		for _, h := range []*Heap[int]{NewHeap[int](arity), intOrder.NewHeap(arity)} {
+			data := make([]int, n)
+			for i := range data {
+				data[i] = rand.Intn(n)
+			}
+			h.Init(Clone(data))
+			checkHeap(t, h, arity)
+			for i := 0; i < n; i++ {
+				v := rand.Intn(n)
+				h.Push(v)
+				data = append(data, v)
+			}
+			checkHeap(t, h, arity)
+
+			// Change some elements in place.
+			list := h.Elements()
+			for i := 0; i < 100; i++ {
+				j := rand.Intn(len(list))
+				k := Index(data, list[j])
+				list[j] = rand.Intn(n)
+				data[k] = list[j]
+				h.Fix(j)
+			}
+			checkHeap(t, h, arity)
+			for i := 0; i < 100; i++ {
+				j := rand.Intn(h.Len())
+				v := h.Remove(j)
+				data = Delete(data, Index(data, v), Index(data, v)+1)
+			}
+			checkHeap(t, h, arity)
+
+			Sort(data)
+			if h.Len() != len(data) || h.Peek() != data[0] {
+				t.Fatalf("arity %d: got %d elements, top %d", arity, h.Len(), h.Peek())
+			}
+			for i, want := range data {
+				if got := h.Pop(); got != want {
+					t.Fatalf("arity %d: Pop %d got %d, want %d", arity, i, got, want)
+				}
+			}
+			if h.Len() != 0 {
+				t.Fatalf("arity %d: %d elements left", arity, h.Len())
+			}
+		}
+	}
+}
+
+func TestHeapMax(t *testing.T) {
+	h := intOrder.Reverse().NewHeap(4)
+	for _, v := range []int{3, 1, 4, 1, 5, 9, 2, 6} {
+		h.Push(v)
+	}
+	for _, want := range []int{9, 6, 5, 4, 3, 2, 1, 1} {
+		if got := h.Pop(); got != want {
+			t.Fatalf("got %d, want %d", got, want)
+		}
+	}
+}
+
+func TestHeapPanics(t *testing.T) {
+	cases := map[string]func(){
+		"arity":  func() { NewHeap[int](3) },
+		"Peek":   func() { NewHeap[int](2).Peek() },
+		"Pop":    func() { NewHeap[int](2).Pop() },
+		"IdPop":  func() { NewIndexedHeap[int](2).Pop() },
+		"IdPush": func() { NewIndexedHeap[int](2).Push(-1, 0) },
+		"Update": func() { NewIndexedHeap[int](2).Update(0, 0) },
+	}
+	for name, f := range cases {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s didn't panic", name)
+				}
+			}()
+			f()
+		}()
+	}
+}
+
+type edge struct {
+	to, weight int
+}
+
+func TestIndexedHeapDijkstra(t *testing.T) {
+	n, m := 500, 5000
+	graph := make([][]edge, n)
+	for i := 0; i < m; i++ {
+		from := rand.Intn(n)
+		graph[from] = append(graph[from], edge{rand.Intn(n), rand.Intn(100)})
+	}
+
+	// Bellman-Ford as reference.
+	const inf = 1 << 30
+	want := make([]int, n)
+	for i := range want {
+		want[i] = inf
+	}
+	want[0] = 0
+	for changed := true; changed; {
+		changed = false
+		for from, edges := range graph {
+			for _, e := range edges {
+				if want[from] != inf && want[from]+e.weight < want[e.to] {
+					want[e.to] = want[from] + e.weight
+					changed = true
+				}
+			}
+		}
+	}
+
+	for _, arity := range []int{2, 4} {
+		for _, h := range []*IndexedHeap[int]{NewIndexedHeap[int](arity), intOrder.NewIndexedHeap(arity)} {
+			dist := make([]int, n)
+			for i := range dist {
+				dist[i] = inf
+			}
+			dist[0] = 0
+			h.Push(0, 0)
+			for h.Len() != 0 {
+				from, d := h.Pop()
+				if h.Contains(from) {
+					t.Fatalf("%d is still in heap after Pop", from)
+				}
+				for _, e := range graph[from] {
+					if nd := d + e.weight; nd < dist[e.to] {
+						dist[e.to] = nd
+						h.Push(e.to, nd)
+						if v, ok := h.Get(e.to); !ok || v != nd {
+							t.Fatalf("Get(%d) got (%d, %v), want %d", e.to, v, ok, nd)
+						}
+					}
+				}
+			}
+			if !Equal(dist, want) {
+				t.Errorf("arity %d: wrong distances", arity)
+			}
+		}
+	}
+}
+
+func TestIndexedHeapRemove(t *testing.T) {
+	n := 1000
+	h := NewIndexedHeap[int](4)
+	vals := make([]int, n)
+	for i := range vals {
+		vals[i] = rand.Intn(n)
+		h.Push(i, vals[i])
+	}
+	// Increase some keys and remove some ids.
+	for i := 0; i < n; i += 3 {
+		vals[i] += n
+		h.Update(i, vals[i])
+	}
+	for i := 1; i < n; i += 5 {
+		if v, ok := h.Remove(i); !ok || v != vals[i] {
+			t.Fatalf("Remove(%d) got (%d, %v), want %d", i, v, ok, vals[i])
+		}
+		vals[i] = -1
+	}
+	if _, ok := h.Remove(1); ok {
+		t.Errorf("removed id 1 twice")
+	}
+	if _, ok := h.Get(n); ok {
+		t.Errorf("got id %d not pushed", n)
+	}
+
+	last := -1
+	for h.Len() != 0 {
+		id, v := h.Pop()
+		if v != vals[id] || v < last {
+			t.Fatalf("Pop got (%d, %d), want value %d not less than %d", id, v, vals[id], last)
+		}
+		vals[id] = -1
+		last = v
+	}
+	for id, v := range vals {
+		if v != -1 {
+			t.Fatalf("id %d is lost", id)
+		}
+	}
+}
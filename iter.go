@@ -0,0 +1,174 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build go1.23
+
+package slices
+
+import (
+	"cmp"
+	"iter"
+)
+
+// All returns an iterator over index-value pairs in the slice
+// in the usual order.
+func All[S ~[]E, E any](s S) iter.Seq2[int, E] {
+	return func(yield func(int, E) bool) {
+		for i, v := range s {
+			if !yield(i, v) {
+				return
+			}
+		}
+	}
+}
+
+// Backward returns an iterator over index-value pairs in the slice,
+// traversing it backward with descending indices.
+func Backward[S ~[]E, E any](s S) iter.Seq2[int, E] {
+	return func(yield func(int, E) bool) {
+		for i := len(s) - 1; i >= 0; i-- {
+			if !yield(i, s[i]) {
+				return
+			}
+		}
+	}
+}
+
+// Values returns an iterator that yields the slice elements in order.
+func Values[S ~[]E, E any](s S) iter.Seq[E] {
+	return func(yield func(E) bool) {
+		for _, v := range s {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// AppendSeq appends the values from seq to the slice and
+// returns the extended slice.
+// Use Grow in advance to avoid reallocation when the length of seq is known.
+func AppendSeq[S ~[]E, E any](s S, seq iter.Seq[E]) S {
+	for v := range seq {
+		s = append(s, v)
+	}
+	return s
+}
+
+// Collect collects values from seq into a new slice and returns it.
+// Use AppendSeq with Grow instead to preallocate the slice.
+func Collect[E any](seq iter.Seq[E]) []E {
+	return AppendSeq([]E(nil), seq)
+}
+
+// Sorted collects values from seq into a new slice, sorts the slice,
+// and returns it.
+func Sorted[E cmp.Ordered](seq iter.Seq[E]) []E {
+	s := Collect(seq)
+	Sort(s)
+	return s
+}
+
+// SortedStable collects values from seq into a new slice, sorts the slice
+// while keeping the original order of equal elements, and returns it.
+func SortedStable[E cmp.Ordered](seq iter.Seq[E]) []E {
+	s := Collect(seq)
+	SortStable(s)
+	return s
+}
+
+// The general version of Sorted.
+func (od *Order[E]) Sorted(seq iter.Seq[E]) []E {
+	s := Collect(seq)
+	od.Sort(s)
+	return s
+}
+
+// The general version of SortedStable.
+func (od *Order[E]) SortedStable(seq iter.Seq[E]) []E {
+	s := Collect(seq)
+	od.SortStable(s)
+	return s
+}
+
+// All returns an iterator over the sorted elements after Sort.
+// Check Err after the iteration finishes.
+func (s *Sorter[E]) All() iter.Seq[E] {
+	return func(yield func(E) bool) {
+		for v, ok := s.Next(); ok; v, ok = s.Next() {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// All returns an iterator over elements in ascending order.
+func (s *SortedSlice[E]) All() iter.Seq[E] {
+	return func(yield func(E) bool) {
+		for i := 0; i < len(s.list); i++ {
+			if !yield(s.list[i]) {
+				return
+			}
+		}
+	}
+}
+
+// Backward returns an iterator over elements in descending order.
+func (s *SortedSlice[E]) Backward() iter.Seq[E] {
+	return func(yield func(E) bool) {
+		for i := len(s.list) - 1; i >= 0; i-- {
+			if !yield(s.list[i]) {
+				return
+			}
+		}
+	}
+}
+
+// Between returns an iterator over elements in [a, b) in ascending order.
+func (s *SortedSlice[E]) Between(a, b E) iter.Seq[E] {
+	return func(yield func(E) bool) {
+		lo, hi := s.Range(a, b)
+		for i := lo; i < hi && i < len(s.list); i++ {
+			if !yield(s.list[i]) {
+				return
+			}
+		}
+	}
+}
+
+// All returns an iterator over elements in ascending order.
+func (t *BTree[E]) All() iter.Seq[E] {
+	return func(yield func(E) bool) {
+		t.Ascend(0, yield)
+	}
+}
+
+// MergeIter returns an iterator that merges sorted sequences lazily.
+// It's stable: elements from former sequences go first when they are equal.
+func MergeIter[E cmp.Ordered](seqs ...iter.Seq[E]) iter.Seq[E] {
+	return mergeSeqs(NewMerger[E], seqs)
+}
+
+// The general version of MergeIter.
+func (od *Order[E]) MergeIter(seqs ...iter.Seq[E]) iter.Seq[E] {
+	return mergeSeqs(od.NewMerger, seqs)
+}
+
+func mergeSeqs[E any](create func(...func() (E, bool)) *Merger[E], seqs []iter.Seq[E]) iter.Seq[E] {
+	return func(yield func(E) bool) {
+		sources := make([]func() (E, bool), len(seqs))
+		for i, seq := range seqs {
+			next, stop := iter.Pull(seq)
+			defer stop()
+			sources[i] = next
+		}
+		m := create(sources...)
+		for v, ok := m.Next(); ok; v, ok = m.Next() {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build go1.23
+
+package slices
+
+import (
+	"iter"
+	"testing"
+)
+
+func TestAllBackwardValues(t *testing.T) {
+	data := Clone(ints[:])
+	i := 0
+	for k, v := range All(data) {
+		if k != i || v != data[i] {
+			t.Fatalf("All got (%d, %d) at %d", k, v, i)
+		}
+		i++
+	}
+	if i != len(data) {
+		t.Errorf("All yielded %d elements, want %d", i, len(data))
+	}
+
+	i = len(data) - 1
+	for k, v := range Backward(data) {
+		if k != i || v != data[i] {
+			t.Fatalf("Backward got (%d, %d) at %d", k, v, i)
+		}
+		i--
+	}
+	if i != -1 {
+		t.Errorf("Backward yielded %d elements, want %d", len(data)-1-i, len(data))
+	}
+
+	for v := range Values(data) {
+		if v != data[0] {
+			t.Errorf("Values got %d, want %d", v, data[0])
+		}
+		break
+	}
+}
+
+func TestCollect(t *testing.T) {
+	data := Clone(ints[:])
+	got := Collect(Values(data))
+	if !Equal(got, data) {
+		t.Errorf("Collect got %v, want %v", got, data)
+	}
+	got = AppendSeq(Grow[[]int](nil, 100), Values(data))
+	if !Equal(got, data) || cap(got) < 100 {
+		t.Errorf("AppendSeq after Grow got %v with capacity %d", got, cap(got))
+	}
+	got = AppendSeq(got[:2], Values(data[5:]))
+	if !Equal(got, append(data[:2:2], data[5:]...)) {
+		t.Errorf("AppendSeq got %v", got)
+	}
+	if got := Collect(Values([]int(nil))); len(got) != 0 {
+		t.Errorf("Collect got %v, want empty", got)
+	}
+}
+
+func TestSorted(t *testing.T) {
+	want := Clone(ints[:])
+	Sort(want)
+	if got := Sorted(Values(ints[:])); !Equal(got, want) {
+		t.Errorf("Sorted got %v, want %v", got, want)
+	}
+	if got := SortedStable(Values(ints[:])); !Equal(got, want) {
+		t.Errorf("SortedStable got %v, want %v", got, want)
+	}
+	if got := intOrder.Sorted(Values(ints[:])); !Equal(got, want) {
+		t.Errorf("Order.Sorted got %v, want %v", got, want)
+	}
+
+	data := make(intPairs, 1000)
+	for i := range data {
+		data[i].a = len(data) - i%10
+		data[i].b = i
+	}
+	got := intPairOrder.SortedStable(Values(data))
+	if !intPairOrder.IsSorted(got) || !intPairs(got).inOrder() {
+		t.Errorf("Order.SortedStable wasn't stable")
+	}
+}
+
+func TestMergeIter(t *testing.T) {
+	data := make(intPairs, 1000)
+	for i := range data {
+		data[i].a = i % 10
+		data[i].b = i
+	}
+	var seqs []iter.Seq[intPair]
+	for i := 0; i < len(data); i += 300 {
+		shard := data[i:min(i+300, len(data))]
+		intPairOrder.SortStable(shard)
+		seqs = append(seqs, Values(shard))
+	}
+	got := intPairs(Collect(intPairOrder.MergeIter(seqs...)))
+	if len(got) != len(data) || !intPairOrder.IsSorted(got) || !got.inOrder() {
+		t.Errorf("Order.MergeIter wasn't stable")
+	}
+
+	cnt := 0
+	for range MergeIter(Values(ints[:]), Values(ints[:])) {
+		if cnt++; cnt == 3 {
+			break
+		}
+	}
+	want := Clone(ints[:])
+	Sort(want)
+	got2 := Collect(MergeIter(Values(want[:5]), Values(want[5:])))
+	if !Equal(got2, want) {
+		t.Errorf("MergeIter got %v, want %v", got2, want)
+	}
+}
+
+func TestSortedSliceIter(t *testing.T) {
+	s := NewSortedSlice(Clone(ints[:]))
+	want := Clone(ints[:])
+	Sort(want)
+	if got := Collect(s.All()); !Equal(got, want) {
+		t.Errorf("All got %v, want %v", got, want)
+	}
+	got := Collect(s.Backward())
+	Reverse(got)
+	if !Equal(got, want) {
+		t.Errorf("Backward got %v, want %v", got, want)
+	}
+	lo, hi := s.Range(0, 100)
+	if got := Collect(s.Between(0, 100)); !Equal(got, want[lo:hi]) {
+		t.Errorf("Between got %v, want %v", got, want[lo:hi])
+	}
+	if got := Collect(s.Between(100, 0)); len(got) != 0 {
+		t.Errorf("Between got %v, want empty", got)
+	}
+}
+
+func TestBTreeIter(t *testing.T) {
+	tr := NewBTree[int]()
+	want := Compact(Sorted(Values(ints[:])))
+	tr.Load(want)
+	if got := Collect(tr.All()); !Equal(got, want) {
+		t.Errorf("All got %v, want %v", got, want)
+	}
+	for x := range tr.All() {
+		if x != want[0] {
+			t.Errorf("All got %d, want %d", x, want[0])
+		}
+		break
+	}
+}
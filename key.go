@@ -0,0 +1,65 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slices
+
+import (
+	"cmp"
+	"unsafe"
+)
+
+// keyed is an extracted key with the index of its owner.
+type keyed[K cmp.Ordered] struct {
+	key K
+	idx int
+}
+
+// keyedOrder compares keyed values by key.
+type keyedOrder[K cmp.Ordered] struct{}
+
+// SortByKey sorts list in ascending order of keys. The key function is called
+// at most once for each element, so it's fine to do some calculation in it.
+// It needs O(n) size extra memory.
+// It's usually faster than Order.Sort when comparison is not trivial.
+func SortByKey[E any, K cmp.Ordered](list []E, key func(*E) K) {
+	sortByKey(list, key, false)
+}
+
+// SortStableByKey sorts list in ascending order of keys, while keeping the
+// original order of elements with equal keys. The key function is called
+// at most once for each element, so it's fine to do some calculation in it.
+// It needs O(n) size extra memory.
+func SortStableByKey[E any, K cmp.Ordered](list []E, key func(*E) K) {
+	sortByKey(list, key, true)
+}
+
+func sortByKey[E any, K cmp.Ordered](list []E, key func(*E) K, stable bool) {
+	if len(list) < 2 {
+		return
+	}
+	keys := make([]keyed[K], len(list))
+	for i := 0; i < len(list); i++ {
+		keys[i] = keyed[K]{key: key(&list[i]), idx: i}
+	}
+	if stable {
+		keyedOrder[K]{}.sortStable(keys, false)
+	} else if !tryBlockIntroSortByKey(keys) {
+		keyedOrder[K]{}.sortFast(keys)
+	}
+	// Following cycles saves memory, but it's slow unless data fit in L1
+	// cache, since every step waits for a random access.
+	if int(unsafe.Sizeof(list[0]))*len(list) > 32*1024 {
+		temp := make([]E, len(list))
+		for i := 0; i < len(keys); i++ {
+			temp[i] = list[keys[i].idx]
+		}
+		copy(list, temp)
+		return
+	}
+	ref := make([]*E, len(list))
+	for i := 0; i < len(keys); i++ {
+		ref[i] = &list[keys[i].idx]
+	}
+	reorder(list, ref)
+}
@@ -0,0 +1,61 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slices
+
+import (
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+func TestSortByKey(t *testing.T) {
+	for _, n := range []int{0, 1, 10, 1000, 100000} {
+		t.Run(strconv.Itoa(n), func(t *testing.T) {
+			data := make([]bigObject, n)
+			for i := 0; i < n; i++ {
+				data[i].val = rand.Intn(n)
+				data[i].pad[0] = byte(data[i].val)
+			}
+			calls := 0
+			SortByKey(data, func(o *bigObject) int {
+				calls++
+				return o.val
+			})
+			if calls > n {
+				t.Errorf("key function called %d times on %d elements", calls, n)
+			}
+			for i := 0; i < n; i++ {
+				if i > 0 && data[i].val < data[i-1].val {
+					t.Fatalf("SortByKey didn't sort at %d", i)
+				}
+				if data[i].pad[0] != byte(data[i].val) {
+					t.Fatalf("SortByKey broke element at %d", i)
+				}
+			}
+		})
+	}
+}
+
+func TestSortByStringKey(t *testing.T) {
+	data := Clone(strs[:])
+	SortByKey(data, func(s *string) string { return *s })
+	if !IsSorted(data) {
+		t.Errorf("sorted %v", strs)
+		t.Errorf("   got %v", data)
+	}
+}
+
+func TestStabilityByKey(t *testing.T) {
+	n, m := 100000, 1000
+	if testing.Short() {
+		n, m = 1000, 100
+	}
+	testStability(t, n, m, func(list []intPair) {
+		SortStableByKey(list, func(p *intPair) int { return p.a })
+	})
+	testStability(t, 100, 10, func(list []intPair) {
+		SortStableByKey(list, func(p *intPair) int { return p.a })
+	})
+}
@@ -0,0 +1,27 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build linux
+
+package slices
+
+import (
+	"math"
+	"syscall"
+)
+
+func init() {
+	var info syscall.Sysinfo_t
+	if err := syscall.Sysinfo(&info); err != nil {
+		return
+	}
+	total := uint64(info.Totalram) * uint64(info.Unit)
+	if total == 0 {
+		return
+	}
+	if total > math.MaxInt {
+		total = math.MaxInt
+	}
+	memInfo.total = int(total)
+}
@@ -0,0 +1,169 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slices
+
+import (
+	"cmp"
+)
+
+// Merge merges sorted lists in srcs, appends the result to dst and returns
+// the extended slice. It's stable: elements from former lists go first when
+// they are equal. dst should not overlap with any list in srcs.
+func Merge[E cmp.Ordered](dst []E, srcs ...[]E) []E {
+	out, dst := mergeSpace(dst, srcs)
+	mergeTo(out, srcs)
+	return dst
+}
+
+// MergeInPlace merges two sorted parts list[:border] and list[border:]
+// stably without extra memory.
+func MergeInPlace[E cmp.Ordered](list []E, border int) {
+	mergeInPlace(list, border)
+}
+
+// Merger merges sorted sources lazily. A source returns its elements one by
+// one, and false when it's exhausted. It's stable: elements from former
+// sources go first when they are equal.
+type Merger[E any] struct {
+	m *merger[E]
+}
+
+// NewMerger creates a Merger for any ordered type.
+// The first element of every source is taken at once.
+func NewMerger[E cmp.Ordered](sources ...func() (E, bool)) *Merger[E] {
+	return &Merger[E]{m: newMerger(func(a, b *E) bool {
+		return cmp.Less(*a, *b)
+	}, sources)}
+}
+
+// Next returns the next element in order. It returns false when all sources
+// are exhausted.
+func (m *Merger[E]) Next() (E, bool) {
+	return m.m.next()
+}
+
+// The general version of Merge.
+func (od *Order[E]) Merge(dst []E, srcs ...[]E) []E {
+	out, dst := mergeSpace(dst, srcs)
+	switch od.kind() {
+	case kindLess:
+		lessFunc[E](od.Less).mergeTo(out, srcs)
+	case kindRefLess:
+		refLessFunc[E](od.RefLess).mergeTo(out, srcs)
+	case kindCompare:
+		compareFunc[E](od.Compare).mergeTo(out, srcs)
+	default:
+		refCompareFunc[E](od.RefCompare).mergeTo(out, srcs)
+	}
+	return dst
+}
+
+// The general version of MergeInPlace.
+func (od *Order[E]) MergeInPlace(list []E, border int) {
+	switch od.kind() {
+	case kindLess:
+		lessFunc[E](od.Less).mergeInPlace(list, border)
+	case kindRefLess:
+		refLessFunc[E](od.RefLess).mergeInPlace(list, border)
+	case kindCompare:
+		compareFunc[E](od.Compare).mergeInPlace(list, border)
+	default:
+		refCompareFunc[E](od.RefCompare).mergeInPlace(list, border)
+	}
+}
+
+// mergeSpace extends dst for merged result of srcs.
+func mergeSpace[E any](dst []E, srcs [][]E) (out, extended []E) {
+	total := 0
+	for _, src := range srcs {
+		total += len(src)
+	}
+	size := len(dst)
+	dst = Grow(dst, total)[:size+total]
+	return dst[size:], dst
+}
+
+// The general version of NewMerger.
+func (od *Order[E]) NewMerger(sources ...func() (E, bool)) *Merger[E] {
+	return &Merger[E]{m: newMerger(od.derive().RefLess, sources)}
+}
+
+// merger merges sorted sources with a binary heap.
+// Elements from former sources go first when they are equal.
+type merger[E any] struct {
+	less    func(a, b *E) bool
+	sources []func() (E, bool)
+	heads   []E
+	heap    []int
+}
+
+func newMerger[E any](less func(a, b *E) bool, sources []func() (E, bool)) *merger[E] {
+	m := &merger[E]{
+		less:    less,
+		sources: sources,
+		heads:   make([]E, len(sources)),
+		heap:    make([]int, 0, len(sources)),
+	}
+	for i, src := range sources {
+		if v, ok := src(); ok {
+			m.heads[i] = v
+			m.heap = append(m.heap, i)
+		}
+	}
+	for i := len(m.heap)/2 - 1; i >= 0; i-- {
+		m.down(i)
+	}
+	return m
+}
+
+func (m *merger[E]) before(a, b int) bool {
+	if m.less(&m.heads[a], &m.heads[b]) {
+		return true
+	}
+	if m.less(&m.heads[b], &m.heads[a]) {
+		return false
+	}
+	return a < b
+}
+
+func (m *merger[E]) down(pos int) {
+	curr := m.heap[pos]
+	kid, last := pos*2+1, len(m.heap)-1
+	for kid <= last {
+		if kid < last && m.before(m.heap[kid+1], m.heap[kid]) {
+			kid++
+		}
+		if !m.before(m.heap[kid], curr) {
+			break
+		}
+		m.heap[pos] = m.heap[kid]
+		pos, kid = kid, kid*2+1
+	}
+	m.heap[pos] = curr
+}
+
+// next pops the smallest head.
+func (m *merger[E]) next() (E, bool) {
+	if len(m.heap) == 0 {
+		var elem E
+		return elem, false
+	}
+	top := m.heap[0]
+	elem := m.heads[top]
+	if v, ok := m.sources[top](); ok {
+		m.heads[top] = v
+	} else {
+		var zero E
+		m.heads[top] = zero
+		last := len(m.heap) - 1
+		m.heap[0] = m.heap[last]
+		m.heap = m.heap[:last]
+		if last == 0 {
+			return elem, true
+		}
+	}
+	m.down(0)
+	return elem, true
+}
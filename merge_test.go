@@ -0,0 +1,148 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slices
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// makeShards splits data into k sorted shards by random.
+func makeShards[E any](data []E, k int, sort func([]E)) [][]E {
+	shards := make([][]E, k)
+	if k == 0 {
+		return shards
+	}
+	for _, v := range data {
+		i := rand.Intn(k)
+		shards[i] = append(shards[i], v)
+	}
+	for _, shard := range shards {
+		sort(shard)
+	}
+	return shards
+}
+
+// sources returns functions which yield elements in lists one by one.
+func sources[E any](lists [][]E) []func() (E, bool) {
+	out := make([]func() (E, bool), len(lists))
+	for i := range lists {
+		list := lists[i]
+		out[i] = func() (elem E, ok bool) {
+			if len(list) == 0 {
+				return elem, false
+			}
+			elem, list = list[0], list[1:]
+			return elem, true
+		}
+	}
+	return out
+}
+
+// drain collects all elements from m.
+func drain[E any](m *Merger[E]) []E {
+	var out []E
+	for v, ok := m.Next(); ok; v, ok = m.Next() {
+		out = append(out, v)
+	}
+	return out
+}
+
+func TestMerge(t *testing.T) {
+	data := make([]int, 10000)
+	for i := range data {
+		data[i] = rand.Intn(1000)
+	}
+	sorted := Clone(data)
+	Sort(sorted)
+	for _, k := range []int{0, 1, 2, 3, 10, 100} {
+		shards := makeShards(data, k, Sort[int])
+		want := sorted
+		if k == 0 {
+			want = nil
+		}
+		prefix := []int{-1, -2}
+		got := Merge(Clone(prefix), shards...)
+		if !Equal(got[:2], prefix) || !Equal(got[2:], want) {
+			t.Errorf("Merge mismatch with Sort on %d shards", k)
+		}
+		if got := intCompareOrder.Merge(nil, shards...); !Equal(got, want) {
+			t.Errorf("Order.Merge mismatch with Sort on %d shards", k)
+		}
+		if got := drain(NewMerger(sources(shards)...)); !Equal(got, want) {
+			t.Errorf("Merger mismatch with Sort on %d shards", k)
+		}
+	}
+}
+
+func TestMergeStability(t *testing.T) {
+	data := make(intPairs, 10000)
+	for i := range data {
+		data[i].a = rand.Intn(100)
+	}
+	od := Order[intPair]{
+		RefLess: func(x, y *intPair) bool {
+			return x.a < y.a
+		},
+	}
+	for _, k := range []int{2, 3, 10} {
+		// Shards keep initial order, and later shards own later elements.
+		shards := make([][]intPair, k)
+		step := (len(data) + k - 1) / k
+		for i := range shards {
+			shard := Clone(data[min(i*step, len(data)):min((i+1)*step, len(data))])
+			intPairs(shard).initB()
+			for j := range shard {
+				shard[j].b += i * step
+			}
+			intPairOrder.SortStable(shard)
+			shards[i] = shard
+		}
+		got := intPairs(intPairOrder.Merge(nil, shards...))
+		if !intPairOrder.IsSorted(got) || !got.inOrder() {
+			t.Errorf("Order.Merge wasn't stable on %d shards", k)
+		}
+		got = intPairs(od.Merge(nil, shards...))
+		if !od.IsSorted(got) || !got.inOrder() {
+			t.Errorf("Order.Merge with RefLess wasn't stable on %d shards", k)
+		}
+		got = intPairs(drain(intPairOrder.NewMerger(sources(shards)...)))
+		if len(got) != len(data) || !intPairOrder.IsSorted(got) || !got.inOrder() {
+			t.Errorf("Order.NewMerger wasn't stable on %d shards", k)
+		}
+	}
+}
+
+func TestMergeInPlace(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 10, 1000} {
+		for _, border := range []int{0, 1, n / 3, n - 1, n} {
+			if border < 0 || border > n {
+				continue
+			}
+			data := make(intPairs, n)
+			for i := range data {
+				data[i].a = rand.Intn(100)
+			}
+			data.initB()
+			intPairOrder.SortStable(data[:border])
+			intPairOrder.SortStable(data[border:])
+			intPairOrder.MergeInPlace(data, border)
+			if !intPairOrder.IsSorted(data) || !data.inOrder() {
+				t.Errorf("Order.MergeInPlace failed on (%d, %d)", n, border)
+			}
+
+			ints := make([]int, n)
+			for i := range ints {
+				ints[i] = rand.Intn(100)
+			}
+			Sort(ints[:border])
+			Sort(ints[border:])
+			MergeInPlace(ints, border)
+			if !IsSorted(ints) {
+				t.Errorf("MergeInPlace failed on (%d, %d)", n, border)
+			}
+		}
+	}
+}
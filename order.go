@@ -0,0 +1,148 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slices
+
+import (
+	"cmp"
+)
+
+// OrderBy creates an Order which compares elements by keys in ascending order.
+// Only .RefLess and .RefCompare are set, because calling key with address of
+// value input forces the value to be allocated in heap.
+func OrderBy[E any, K cmp.Ordered](key func(*E) K) *Order[E] {
+	return &Order[E]{
+		RefLess: func(a, b *E) bool {
+			return cmp.Less(key(a), key(b))
+		},
+		RefCompare: func(a, b *E) int {
+			return cmp.Compare(key(a), key(b))
+		},
+	}
+}
+
+// Reverse creates an Order in the opposite direction.
+// Three-way versions are set only when od has them, because the ones derived
+// from boolean versions take two calls but would be preferred.
+func (od *Order[E]) Reverse() *Order[E] {
+	x := od.derive()
+	out := &Order[E]{
+		RefLess: func(a, b *E) bool {
+			return x.RefLess(b, a)
+		},
+	}
+	if x.Less != nil {
+		out.Less = func(a, b E) bool {
+			return x.Less(b, a)
+		}
+	}
+	if od.Compare != nil || od.RefCompare != nil {
+		out.RefCompare = func(a, b *E) int {
+			return x.RefCompare(b, a)
+		}
+	}
+	if od.Compare != nil {
+		out.Compare = func(a, b E) int {
+			return x.Compare(b, a)
+		}
+	}
+	return out
+}
+
+// ThenBy creates an Order which compares elements with od first,
+// and breaks ties with other.
+// Like Reverse, three-way versions are set only when both of them have.
+func (od *Order[E]) ThenBy(other *Order[E]) *Order[E] {
+	x, y := od.derive(), other.derive()
+	out := &Order[E]{
+		RefLess: func(a, b *E) bool {
+			if c := x.RefCompare(a, b); c != 0 {
+				return c < 0
+			}
+			return y.RefLess(a, b)
+		},
+	}
+	if x.Less != nil && y.Less != nil {
+		out.Less = func(a, b E) bool {
+			if c := x.Compare(a, b); c != 0 {
+				return c < 0
+			}
+			return y.Less(a, b)
+		}
+	}
+	if (od.Compare != nil || od.RefCompare != nil) &&
+		(other.Compare != nil || other.RefCompare != nil) {
+		out.RefCompare = func(a, b *E) int {
+			if c := x.RefCompare(a, b); c != 0 {
+				return c
+			}
+			return y.RefCompare(a, b)
+		}
+	}
+	if od.Compare != nil && other.Compare != nil {
+		out.Compare = func(a, b E) int {
+			if c := x.Compare(a, b); c != 0 {
+				return c
+			}
+			return y.Compare(a, b)
+		}
+	}
+	return out
+}
+
+// derive fills all missing comparison functions of od into a copy.
+// Value input versions are left nil when only pointer input versions are set.
+func (od *Order[E]) derive() Order[E] {
+	out := *od
+	if out.Less == nil && out.Compare != nil {
+		compare := out.Compare
+		out.Less = func(a, b E) bool {
+			return compare(a, b) < 0
+		}
+	}
+	if out.Compare == nil && out.Less != nil {
+		less := out.Less
+		out.Compare = func(a, b E) int {
+			if less(a, b) {
+				return -1
+			}
+			if less(b, a) {
+				return 1
+			}
+			return 0
+		}
+	}
+	if out.RefLess == nil {
+		if refCompare := out.RefCompare; refCompare != nil {
+			out.RefLess = func(a, b *E) bool {
+				return refCompare(a, b) < 0
+			}
+		} else if less := out.Less; less != nil {
+			out.RefLess = func(a, b *E) bool {
+				return less(*a, *b)
+			}
+		} else {
+			panic("uninitialized Order")
+		}
+	}
+	if out.RefCompare == nil {
+		if compare := out.Compare; compare != nil {
+			out.RefCompare = func(a, b *E) int {
+				return compare(*a, *b)
+			}
+		} else {
+			refLess := out.RefLess
+			out.RefCompare = func(a, b *E) int {
+				if refLess(a, b) {
+					return -1
+				}
+				if refLess(b, a) {
+					return 1
+				}
+				return 0
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,128 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slices
+
+import (
+	"cmp"
+	"math/rand"
+	std "slices"
+	"testing"
+)
+
+type person struct {
+	name string
+	age  int
+}
+
+func comparePersons(a, b person) int {
+	if c := cmp.Compare(b.age, a.age); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.name, b.name)
+}
+
+func randomPersons(n int) []person {
+	names := []string{"ann", "bob", "cat", "dan", "eve", "fay"}
+	list := make([]person, n)
+	for i := 0; i < n; i++ {
+		list[i].name = names[rand.Intn(len(names))]
+		list[i].age = rand.Intn(10)
+	}
+	return list
+}
+
+func checkOrder[E any](t *testing.T, od *Order[E], want func(a, b E) int, list []E) {
+	t.Helper()
+	x := od.derive()
+	for i := 0; i < len(list); i++ {
+		for j := 0; j < len(list); j++ {
+			a, b := list[i], list[j]
+			c := want(a, b)
+			if x.RefLess(&a, &b) != (c < 0) {
+				t.Fatalf("RefLess(%v, %v) mismatch", a, b)
+			}
+			if cmp.Compare(x.RefCompare(&a, &b), 0) != c {
+				t.Fatalf("RefCompare(%v, %v) mismatch", a, b)
+			}
+			if od.Less != nil && od.Less(a, b) != (c < 0) {
+				t.Fatalf("Less(%v, %v) mismatch", a, b)
+			}
+			if od.Compare != nil && cmp.Compare(od.Compare(a, b), 0) != c {
+				t.Fatalf("Compare(%v, %v) mismatch", a, b)
+			}
+		}
+	}
+}
+
+func TestOrderBy(t *testing.T) {
+	byAge := OrderBy(func(p *person) int { return p.age })
+	byName := OrderBy(func(p *person) string { return p.name })
+	od := byAge.Reverse().ThenBy(byName)
+
+	list := randomPersons(50)
+	checkOrder(t, od, comparePersons, list)
+
+	data := randomPersons(10000)
+	want := Clone(data)
+	std.SortStableFunc(want, comparePersons)
+	od.Sort(data)
+	if !Equal(data, want) {
+		t.Errorf("composed Order didn't sort")
+	}
+}
+
+func TestOrderReverse(t *testing.T) {
+	list := Clone(ints[:])
+	want := func(a, b int) int { return cmp.Compare(b, a) }
+	checkOrder(t, intOrder.Reverse(), want, list)
+	if rev := intOrder.Reverse(); rev.Compare != nil || rev.RefCompare != nil {
+		t.Errorf("three-way versions should not be derived from boolean versions")
+	}
+	checkOrder(t, intCompareOrder.Reverse(), want, list)
+	checkOrder(t, intCompareOrder.Reverse().Reverse(), cmp.Compare[int], list)
+
+	od := Order[int]{RefLess: func(a, b *int) bool { return *a < *b }}
+	rev := od.Reverse()
+	if rev.Less != nil || rev.Compare != nil {
+		t.Errorf("value versions should not be derived from pointer versions")
+	}
+	checkOrder(t, rev, want, list)
+
+	rev.Sort(list)
+	if !rev.IsSorted(list) || list[0] != Max(ints[:]) {
+		t.Errorf("reversed Order didn't sort: %v", list)
+	}
+}
+
+func TestOrderThenBy(t *testing.T) {
+	byAge := &Order[person]{
+		Less: func(a, b person) bool { return a.age > b.age },
+	}
+	byName := &Order[person]{
+		Compare: func(a, b person) int { return cmp.Compare(a.name, b.name) },
+	}
+	od := byAge.ThenBy(byName)
+	if od.Less == nil {
+		t.Errorf("value versions should be derived")
+	}
+	if od.Compare != nil || od.RefCompare != nil {
+		t.Errorf("three-way versions should not be derived from boolean versions")
+	}
+	checkOrder(t, od, comparePersons, randomPersons(50))
+
+	byAge3 := &Order[person]{
+		Compare: func(a, b person) int { return cmp.Compare(b.age, a.age) },
+	}
+	od = byAge3.ThenBy(byName)
+	if od.Less == nil || od.Compare == nil || od.RefCompare == nil {
+		t.Errorf("all versions should be derived from three-way versions")
+	}
+	checkOrder(t, od, comparePersons, randomPersons(50))
+
+	var empty Order[person]
+	if !panics(func() { empty.ThenBy(byName) }) {
+		t.Errorf("ThenBy with uninitialized Order: got no panic, want panic")
+	}
+}
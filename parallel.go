@@ -0,0 +1,152 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slices
+
+import (
+	"cmp"
+	"runtime"
+	"sync"
+	"unsafe"
+)
+
+// Segments not longer than it are sorted in a single goroutine.
+const parallelSize = 16 * 1024
+
+func parallelism(workers int) int {
+	if workers <= 0 {
+		return runtime.GOMAXPROCS(0)
+	}
+	return workers
+}
+
+// workerPool limits the number of goroutines used by parallel sorting.
+// The calling goroutine is counted as a worker.
+type workerPool struct {
+	slots chan struct{}
+}
+
+func newWorkerPool(workers int) *workerPool {
+	return &workerPool{slots: make(chan struct{}, workers-1)}
+}
+
+// fork runs tasks and waits for all of them to finish.
+// A task is run in a new goroutine only when there is an idle worker,
+// otherwise it is run in the current goroutine.
+func (p *workerPool) fork(tasks ...func()) {
+	var wg sync.WaitGroup
+	last := len(tasks) - 1
+	for i := 0; i < last; i++ {
+		select {
+		case p.slots <- struct{}{}:
+			wg.Add(1)
+			go func(task func()) {
+				task()
+				<-p.slots
+				wg.Done()
+			}(tasks[i])
+		default:
+			tasks[i]()
+		}
+	}
+	tasks[last]()
+	wg.Wait()
+}
+
+// ParallelSort sorts a slice of any ordered type in ascending order with
+// at most workers goroutines. GOMAXPROCS is used when workers is not positive.
+// The result is the same as Sort.
+func ParallelSort[E cmp.Ordered](list []E, workers int) {
+	if workers = parallelism(workers); workers < 2 || len(list) < parallelSize*2 {
+		Sort(list)
+		return
+	}
+	pool := newWorkerPool(workers)
+	if !tryParallelBlockIntroSort(list, pool) {
+		parallelSortFast(list, pool)
+	}
+}
+
+// ParallelSortStable sorts a slice of any ordered type in ascending order
+// with at most workers goroutines, while keeping the original order of equal
+// elements. GOMAXPROCS is used when workers is not positive.
+// It needs O(n) size extra memory.
+func ParallelSortStable[E cmp.Ordered](list []E, workers int) {
+	if workers = parallelism(workers); workers < 2 || len(list) < parallelSize*2 {
+		SortStable(list)
+		return
+	}
+	temp := make([]E, len(list))
+	copy(temp, list)
+	parallelMergeSort(temp, list, newWorkerPool(workers))
+}
+
+// The general version of ParallelSort.
+func (od *Order[E]) ParallelSort(list []E, workers int) {
+	if workers = parallelism(workers); workers < 2 || len(list) < parallelSize*2 {
+		od.Sort(list)
+		return
+	}
+	pool := newWorkerPool(workers)
+	kind := od.kind()
+	if !kind.byRef() || int(unsafe.Sizeof(list[0])) <= int(unsafe.Sizeof(uintptr(0)))*4 {
+		switch kind {
+		case kindLess:
+			lessFunc[E](od.Less).parallelSortFast(list, pool)
+		case kindRefLess:
+			refLessFunc[E](od.RefLess).parallelSortFast(list, pool)
+		case kindCompare:
+			compareFunc[E](od.Compare).parallelSortFast(list, pool)
+		default:
+			refCompareFunc[E](od.RefCompare).parallelSortFast(list, pool)
+		}
+		return
+	}
+	// sort by pointer list, fast in cache
+	ref := refsOf(list)
+	if kind == kindRefLess {
+		lessFunc[*E](od.RefLess).parallelSortFast(ref, pool)
+	} else {
+		compareFunc[*E](od.RefCompare).parallelSortFast(ref, pool)
+	}
+	reorder(list, ref)
+}
+
+// The general version of ParallelSortStable.
+func (od *Order[E]) ParallelSortStable(list []E, workers int) {
+	if workers = parallelism(workers); workers < 2 || len(list) < parallelSize*2 {
+		od.SortStable(list)
+		return
+	}
+	pool := newWorkerPool(workers)
+	kind := od.kind()
+	if !kind.byRef() || int(unsafe.Sizeof(list[0])) <= int(unsafe.Sizeof(uintptr(0)))*4 {
+		temp := make([]E, len(list))
+		copy(temp, list)
+		switch kind {
+		case kindLess:
+			lessFunc[E](od.Less).parallelMergeSort(temp, list, pool)
+		case kindRefLess:
+			refLessFunc[E](od.RefLess).parallelMergeSort(temp, list, pool)
+		case kindCompare:
+			compareFunc[E](od.Compare).parallelMergeSort(temp, list, pool)
+		default:
+			refCompareFunc[E](od.RefCompare).parallelMergeSort(temp, list, pool)
+		}
+		return
+	}
+	// sort by pointer list, fast in cache
+	ref := make([]*E, len(list))
+	temp := make([]*E, len(list))
+	for i := 0; i < len(list); i++ {
+		ref[i] = &list[i]
+		temp[i] = &list[i]
+	}
+	if kind == kindRefLess {
+		lessFunc[*E](od.RefLess).parallelMergeSort(temp, ref, pool)
+	} else {
+		compareFunc[*E](od.RefCompare).parallelMergeSort(temp, ref, pool)
+	}
+	reorder(list, ref)
+}
@@ -0,0 +1,99 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slices
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func parallelTestSize() int {
+	if testing.Short() {
+		return parallelSize * 4
+	}
+	return parallelSize * 16
+}
+
+func TestParallelSortInts(t *testing.T) {
+	n := parallelTestSize()
+	for _, gen := range pattern {
+		t.Run(gen.name, func(t *testing.T) {
+			data := make([]int, n)
+			gen.fn(data)
+			want := Clone(data)
+			Sort(want)
+			ParallelSort(data, 4)
+			if !Equal(data, want) {
+				t.Errorf("ParallelSort mismatch with Sort")
+			}
+		})
+	}
+}
+
+func TestParallelSortFloats(t *testing.T) {
+	n := parallelTestSize()
+	data := make([]float64, n)
+	for i := 0; i < n; i++ {
+		data[i] = math.Round(rand.NormFloat64()*1000) / 100
+	}
+	want := Clone(data)
+	Sort(want)
+	ParallelSort(data, 0)
+	if !Equal(data, want) {
+		t.Errorf("ParallelSort mismatch with Sort")
+	}
+
+	data = Clone(want)
+	Reverse(data)
+	ParallelSortStable(data, 0)
+	if !Equal(data, want) {
+		t.Errorf("ParallelSortStable mismatch with Sort")
+	}
+}
+
+func TestParallelSortObject(t *testing.T) {
+	n := parallelTestSize()
+	data1 := make([]smallObject, n)
+	data2 := make([]bigObject, n)
+	for i := 0; i < n; i++ {
+		val := rand.Intn(n)
+		data1[i].val = val
+		data2[i].val = val
+	}
+	od1 := Order[smallObject]{
+		RefLess: func(a, b *smallObject) bool {
+			return a.val < b.val
+		}}
+	od1.ParallelSort(data1, 4)
+	if !od1.IsSorted(data1) {
+		t.Errorf("small objects didn't sort")
+	}
+
+	od2 := Order[bigObject]{
+		RefLess: func(a, b *bigObject) bool {
+			return a.val < b.val
+		}}
+	od2.ParallelSort(data2, 4)
+	if !od2.IsSorted(data2) {
+		t.Errorf("big objects didn't sort")
+	}
+}
+
+func TestParallelStability(t *testing.T) {
+	n, m := parallelTestSize(), 1000
+	testStability(t, n, m, func(list []intPair) {
+		intPairOrder.ParallelSortStable(list, 4)
+	})
+
+	od := Order[intPair]{
+		RefLess: func(x, y *intPair) bool {
+			return x.a < y.a
+		},
+	}
+	testStability(t, n, m, func(list []intPair) {
+		od.ParallelSortStable(list, 4)
+	})
+}
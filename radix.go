@@ -0,0 +1,215 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slices
+
+import (
+	"cmp"
+	"reflect"
+	"unsafe"
+)
+
+type integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+type float interface {
+	~float32 | ~float64
+}
+
+// RadixSort sorts a slice of integers or floating-point numbers in ascending
+// order by radix sort without extra memory.
+// NaNs are ordered before other values, just like Sort.
+func RadixSort[E integer | float](list []E) {
+	if len(list) < 2 {
+		return
+	}
+	radixSort(list, radixKindOf[E]())
+}
+
+// RadixSortStable sorts a slice of integers or floating-point numbers in
+// ascending order by radix sort, while keeping the original order of equal
+// elements. It needs O(n) size extra memory.
+// NaNs are ordered before other values, just like Sort.
+func RadixSortStable[E integer | float](list []E) {
+	if len(list) < 2 {
+		return
+	}
+	radixSortStable(list, radixKindOf[E]())
+}
+
+const (
+	// Radix sort beats comparison sort on long list.
+	radixSize = 64 * 1024
+	// Buckets not longer than it are left to comparison sort.
+	radixLeaf = 256
+)
+
+type radixKind uint8
+
+const (
+	radixNone radixKind = iota
+	radixUnsigned
+	radixSigned
+	radixFloat
+)
+
+func radixKindOf[E cmp.Ordered]() radixKind {
+	switch reflect.TypeOf((*E)(nil)).Elem().Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return radixSigned
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		return radixUnsigned
+	case reflect.Float32, reflect.Float64:
+		return radixFloat
+	default:
+		return radixNone
+	}
+}
+
+// radixKey maps v to an unsigned integer with the same order.
+// All NaNs are mapped to 0, and negative zero is treated as positive zero.
+func radixKey[E cmp.Ordered](v E, kind radixKind) uint64 {
+	var key uint64
+	switch unsafe.Sizeof(v) {
+	case 1:
+		key = uint64(*(*uint8)(unsafe.Pointer(&v)))
+	case 2:
+		key = uint64(*(*uint16)(unsafe.Pointer(&v)))
+	case 4:
+		key = uint64(*(*uint32)(unsafe.Pointer(&v)))
+	default:
+		key = *(*uint64)(unsafe.Pointer(&v))
+	}
+	sign := uint64(1) << (unsafe.Sizeof(v)*8 - 1)
+	switch kind {
+	case radixSigned:
+		key ^= sign
+	case radixFloat:
+		if v != v {
+			return 0
+		}
+		if key&sign == 0 || key == sign {
+			key |= sign
+		} else {
+			key = ^key & (sign<<1 - 1)
+		}
+	}
+	return key
+}
+
+func radixSort[E cmp.Ordered](list []E, kind radixKind) {
+	size := len(list)
+	if size > 50 {
+		a, b, c := size/4, size/2, size*3/4
+		a, ha := median(list, a-1, a, a+1)
+		b, hb := median(list, b-1, b, b+1)
+		c, hc := median(list, c-1, c, c+1)
+		_, hint := median(list, a, b, c)
+		hint &= ha & hb & hc
+
+		if hint == hintRevered {
+			reverse(list)
+			hint = hintSorted
+		}
+		if hint == hintSorted && isSorted(list) {
+			return
+		}
+	}
+	radixSortMSD(list, kind, uint(unsafe.Sizeof(list[0])-1)*8)
+}
+
+// radixSortMSD is an in-place MSD radix sort, known as American flag sort.
+func radixSortMSD[E cmp.Ordered](list []E, kind radixKind, shift uint) {
+	for len(list) > radixLeaf {
+		var count [256]int
+		for _, v := range list {
+			count[uint8(radixKey(v, kind)>>shift)]++
+		}
+		if count[uint8(radixKey(list[0], kind)>>shift)] == len(list) {
+			// All elements share the same digit.
+			if shift == 0 {
+				return
+			}
+			shift -= 8
+			continue
+		}
+
+		var head, tail [256]int
+		sum := 0
+		for i := 0; i < 256; i++ {
+			head[i] = sum
+			sum += count[i]
+			tail[i] = sum
+		}
+		for i := 0; i < 256; i++ {
+			for head[i] < tail[i] {
+				curr := list[head[i]]
+				d := uint8(radixKey(curr, kind) >> shift)
+				for int(d) != i {
+					curr, list[head[d]] = list[head[d]], curr
+					head[d]++
+					d = uint8(radixKey(curr, kind) >> shift)
+				}
+				list[head[i]] = curr
+				head[i]++
+			}
+		}
+
+		if shift == 0 {
+			return
+		}
+		a := 0
+		for i := 0; i < 256; i++ {
+			if b := tail[i]; b-a > 1 {
+				radixSortMSD(list[a:b], kind, shift-8)
+			}
+			a = tail[i]
+		}
+		return
+	}
+	sortFast(list)
+}
+
+func radixSortStable[E cmp.Ordered](list []E, kind radixKind) {
+	temp := make([]E, len(list))
+	radixSortLSD(list, temp, kind)
+}
+
+// radixSortLSD is a stable LSD radix sort with temp as buffer.
+func radixSortLSD[E cmp.Ordered](list, temp []E, kind radixKind) {
+	width := int(unsafe.Sizeof(list[0]))
+	var count [8][256]int
+	for _, v := range list {
+		key := radixKey(v, kind)
+		for i := 0; i < width; i++ {
+			count[i][uint8(key>>(i*8))]++
+		}
+	}
+
+	src, dst := list, temp
+	first := radixKey(list[0], kind)
+	for i := 0; i < width; i++ {
+		shift := uint(i * 8)
+		cnt := &count[i]
+		if cnt[uint8(first>>shift)] == len(list) {
+			continue // All elements share the same digit.
+		}
+		sum := 0
+		for j := 0; j < 256; j++ {
+			cnt[j], sum = sum, sum+cnt[j]
+		}
+		for _, v := range src {
+			d := uint8(radixKey(v, kind) >> shift)
+			dst[cnt[d]] = v
+			cnt[d]++
+		}
+		src, dst = dst, src
+	}
+	if &src[0] != &list[0] {
+		copy(list, src)
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slices
+
+import (
+	"cmp"
+	"math"
+	"math/rand"
+	std "slices"
+	"testing"
+)
+
+func testRadixSort[E integer | float](t *testing.T, gen func() E) {
+	t.Helper()
+	n := radixSize * 2
+	if testing.Short() {
+		n = 1000
+	}
+	data := make([]E, n)
+	for i := 0; i < n; i++ {
+		data[i] = gen()
+	}
+	want := Clone(data)
+	std.Sort(want)
+	eq := func(a, b E) bool { return cmp.Compare(a, b) == 0 }
+
+	got := Clone(data)
+	RadixSort(got)
+	if !EqualFunc(got, want, eq) {
+		t.Errorf("RadixSort mismatch with std.Sort")
+	}
+	got = Clone(data)
+	RadixSortStable(got)
+	if !EqualFunc(got, want, eq) {
+		t.Errorf("RadixSortStable mismatch with std.Sort")
+	}
+	got = Clone(data)
+	Sort(got)
+	if !EqualFunc(got, want, eq) {
+		t.Errorf("Sort mismatch with std.Sort")
+	}
+	got = Clone(data)
+	SortStable(got)
+	if !EqualFunc(got, want, eq) {
+		t.Errorf("SortStable mismatch with std.Sort")
+	}
+}
+
+func TestRadixSortIntegers(t *testing.T) {
+	t.Run("int8", func(t *testing.T) {
+		testRadixSort(t, func() int8 { return int8(rand.Int()) })
+	})
+	t.Run("uint8", func(t *testing.T) {
+		testRadixSort(t, func() uint8 { return uint8(rand.Int()) })
+	})
+	t.Run("int16", func(t *testing.T) {
+		testRadixSort(t, func() int16 { return int16(rand.Int()) })
+	})
+	t.Run("int32", func(t *testing.T) {
+		testRadixSort(t, func() int32 { return int32(rand.Int()) })
+	})
+	t.Run("uint32", func(t *testing.T) {
+		testRadixSort(t, func() uint32 { return uint32(rand.Int()) })
+	})
+	t.Run("int", func(t *testing.T) {
+		testRadixSort(t, func() int { return rand.Int() - rand.Int() })
+	})
+	t.Run("int-small", func(t *testing.T) {
+		testRadixSort(t, func() int { return rand.Intn(100) - 50 })
+	})
+	t.Run("uint64", func(t *testing.T) {
+		testRadixSort(t, rand.Uint64)
+	})
+	t.Run("uintptr", func(t *testing.T) {
+		testRadixSort(t, func() uintptr { return uintptr(rand.Uint64()) })
+	})
+}
+
+func TestRadixSortFloats(t *testing.T) {
+	special := []float64{math.NaN(), math.Inf(1), math.Inf(-1),
+		math.Copysign(0, -1), 0, math.MaxFloat64, -math.MaxFloat64,
+		math.SmallestNonzeroFloat64, -math.SmallestNonzeroFloat64}
+	gen := func() float64 {
+		if rand.Intn(10) == 0 {
+			return special[rand.Intn(len(special))]
+		}
+		return rand.NormFloat64()
+	}
+	t.Run("float64", func(t *testing.T) {
+		testRadixSort(t, gen)
+	})
+	t.Run("float32", func(t *testing.T) {
+		testRadixSort(t, func() float32 { return float32(gen()) })
+	})
+}
+
+func TestRadixSortStability(t *testing.T) {
+	// Negative zero equals to positive zero, their order should be kept.
+	n := 1000
+	data := make([]float64, n)
+	for i := 0; i < n; i++ {
+		switch rand.Intn(3) {
+		case 0:
+			data[i] = math.Copysign(0, -1)
+		case 1:
+			data[i] = 0
+		default:
+			data[i] = rand.NormFloat64()
+		}
+	}
+	var want []bool
+	for _, v := range data {
+		if v == 0 {
+			want = append(want, math.Signbit(v))
+		}
+	}
+	RadixSortStable(data)
+	if !IsSorted(data) {
+		t.Fatalf("RadixSortStable didn't sort")
+	}
+	var got []bool
+	for _, v := range data {
+		if v == 0 {
+			got = append(got, math.Signbit(v))
+		}
+	}
+	if !Equal(got, want) {
+		t.Errorf("RadixSortStable wasn't stable on zeros")
+	}
+}
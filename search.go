@@ -0,0 +1,165 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slices
+
+import (
+	"cmp"
+	"math"
+)
+
+// LowerBound returns the position of the first element not less than target
+// in a sorted slice, or len(list) if there is no such element.
+func LowerBound[E cmp.Ordered](list []E, target E) int {
+	return lowerBound(list, target)
+}
+
+// UpperBound returns the position of the first element greater than target
+// in a sorted slice, or len(list) if there is no such element.
+func UpperBound[E cmp.Ordered](list []E, target E) int {
+	return upperBound(list, target)
+}
+
+// EqualRange returns the range [lo, hi) of elements equal to target in a
+// sorted slice. lo == hi means target is not found, and lo is the position
+// where target would appear.
+func EqualRange[E cmp.Ordered](list []E, target E) (lo, hi int) {
+	return equalRange(list, target)
+}
+
+// The general version of LowerBound.
+func (od *Order[E]) LowerBound(list []E, target E) int {
+	switch od.kind() {
+	case kindLess:
+		return lessFunc[E](od.Less).lowerBound(list, target)
+	case kindRefLess:
+		return refLessFunc[E](od.RefLess).lowerBound(list, target)
+	case kindCompare:
+		return compareFunc[E](od.Compare).lowerBound(list, target)
+	default:
+		return refCompareFunc[E](od.RefCompare).lowerBound(list, target)
+	}
+}
+
+// The general version of UpperBound.
+func (od *Order[E]) UpperBound(list []E, target E) int {
+	switch od.kind() {
+	case kindLess:
+		return lessFunc[E](od.Less).upperBound(list, target)
+	case kindRefLess:
+		return refLessFunc[E](od.RefLess).upperBound(list, target)
+	case kindCompare:
+		return compareFunc[E](od.Compare).upperBound(list, target)
+	default:
+		return refCompareFunc[E](od.RefCompare).upperBound(list, target)
+	}
+}
+
+// The general version of EqualRange.
+func (od *Order[E]) EqualRange(list []E, target E) (lo, hi int) {
+	switch od.kind() {
+	case kindLess:
+		return lessFunc[E](od.Less).equalRange(list, target)
+	case kindRefLess:
+		return refLessFunc[E](od.RefLess).equalRange(list, target)
+	case kindCompare:
+		return compareFunc[E](od.Compare).equalRange(list, target)
+	default:
+		return refCompareFunc[E](od.RefCompare).equalRange(list, target)
+	}
+}
+
+// LowerBoundFunc works like LowerBound, but uses a custom comparison function.
+// The slice must be sorted in increasing order, where "increasing" is defined
+// by cmp. cmp should return 0 if the slice element matches the target,
+// a negative number if the slice element precedes the target, or a positive
+// number if the slice element follows the target.
+func LowerBoundFunc[E, T any](list []E, target T, cmp func(E, T) int) int {
+	a, b := 0, len(list)
+	for a < b {
+		m := int(uint(a+b) / 2)
+		if cmp(list[m], target) < 0 {
+			a = m + 1
+		} else {
+			b = m
+		}
+	}
+	return a
+}
+
+// UpperBoundFunc works like UpperBound, but uses a custom comparison function
+// like LowerBoundFunc.
+func UpperBoundFunc[E, T any](list []E, target T, cmp func(E, T) int) int {
+	a, b := 0, len(list)
+	for a < b {
+		m := int(uint(a+b) / 2)
+		if cmp(list[m], target) > 0 {
+			b = m
+		} else {
+			a = m + 1
+		}
+	}
+	return a
+}
+
+// EqualRangeFunc works like EqualRange, but uses a custom comparison function
+// like LowerBoundFunc.
+func EqualRangeFunc[E, T any](list []E, target T, cmp func(E, T) int) (lo, hi int) {
+	lo = LowerBoundFunc(list, target, cmp)
+	return lo, lo + UpperBoundFunc(list[lo:], target, cmp)
+}
+
+// GallopSearch works like BinarySearch, but starts from position hint and
+// searches by exponential steps. It's faster than BinarySearch when target is
+// close to hint, e.g. a cursor moving forward in a sorted slice.
+func GallopSearch[E cmp.Ordered](list []E, target E, hint int) (int, bool) {
+	return gallopSearch(list, target, hint)
+}
+
+// The general version of GallopSearch.
+func (od *Order[E]) GallopSearch(list []E, target E, hint int) (int, bool) {
+	switch od.kind() {
+	case kindLess:
+		return lessFunc[E](od.Less).gallopSearch(list, target, hint)
+	case kindRefLess:
+		return refLessFunc[E](od.RefLess).gallopSearch(list, target, hint)
+	case kindCompare:
+		return compareFunc[E](od.Compare).gallopSearch(list, target, hint)
+	default:
+		return refCompareFunc[E](od.RefCompare).gallopSearch(list, target, hint)
+	}
+}
+
+// InterpolationSearch works like BinarySearch, but guesses the position of
+// target by linear interpolation between the bounds. It takes O(log log n)
+// steps on uniformly distributed data. It falls back to binary search when
+// interpolation doesn't converge quickly, or meets NaNs and infinities.
+func InterpolationSearch[E integer | float](list []E, target E) (int, bool) {
+	a, b := 0, len(list)
+	// list[:a] are less than target, list[b:] are not.
+	for chance := log2Ceil(uint(len(list))); b-a > 16; chance-- {
+		lo, hi := list[a], list[b-1]
+		if !cmp.Less(lo, target) {
+			b = a
+			break
+		}
+		if cmp.Less(hi, target) {
+			a = b
+			break
+		}
+		span := float64(hi) - float64(lo)
+		if chance <= 0 || !(span > 0 && span <= math.MaxFloat64) {
+			break
+		}
+		m := a + int(float64(b-1-a)*((float64(target)-float64(lo))/span))
+		m = max(min(m, b-1), a)
+		if cmp.Less(list[m], target) {
+			a = m + 1
+		} else {
+			b = m
+		}
+	}
+	a += lowerBound(list[a:b], target)
+	return a, a < len(list) && !cmp.Less(target, list[a])
+}
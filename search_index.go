@@ -0,0 +1,151 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slices
+
+import (
+	"cmp"
+	"math/bits"
+	"unsafe"
+)
+
+// SearchIndex is an immutable search index built from a sorted slice.
+// Elements are stored in Eytzinger layout (the BFS order of a complete binary
+// search tree), which makes searching much more cache friendly than binary
+// search on long list.
+// Positions returned by its methods are indexes in the original sorted slice.
+type SearchIndex[E cmp.Ordered] struct {
+	tree []E // tree[1] is the root, children of tree[k] are tree[2k] and tree[2k+1]
+	size int
+	// Descendants of tree[k] at depth prefetchDepth below are located
+	// in the cache line starting at tree[k<<prefetchDepth].
+	prefetchDepth int
+}
+
+// NewSearchIndex creates a SearchIndex from a sorted slice, which can be
+// modified or dropped after that. The slice must be sorted in ascending order.
+func NewSearchIndex[E cmp.Ordered](sorted []E) *SearchIndex[E] {
+	idx := &SearchIndex[E]{size: len(sorted)}
+	var elem E
+	elemSize := int(unsafe.Sizeof(elem))
+	block := cacheInfo.lineSize / elemSize
+	if block < 2 || block&(block-1) != 0 || cacheInfo.lineSize%elemSize != 0 {
+		block = 1
+	}
+	// Align tree[0] to cache line, so that prefetching a line covers all
+	// descendants in some levels.
+	buf := make([]E, len(sorted)+block)
+	off := 0
+	if block > 1 {
+		mis := int(uintptr(unsafe.Pointer(&buf[0])) % uintptr(cacheInfo.lineSize))
+		if mis%elemSize == 0 {
+			off = (cacheInfo.lineSize - mis) % cacheInfo.lineSize / elemSize
+			idx.prefetchDepth = bits.Len(uint(block)) - 1
+		}
+	}
+	idx.tree = buf[off : off+len(sorted)+1]
+	idx.build(sorted, 0, 1)
+	return idx
+}
+
+// build fills the subtree rooted at tree[k] with sorted[i:] in order,
+// returns the index of the next element to use.
+func (idx *SearchIndex[E]) build(sorted []E, i, k int) int {
+	if k <= idx.size {
+		i = idx.build(sorted, i, k*2)
+		idx.tree[k] = sorted[i]
+		i = idx.build(sorted, i+1, k*2+1)
+	}
+	return i
+}
+
+// Len returns the number of elements in the index.
+func (idx *SearchIndex[E]) Len() int {
+	return idx.size
+}
+
+// rank returns the index in sorted slice of tree[k].
+func (idx *SearchIndex[E]) rank(k int) int {
+	if k == 0 {
+		return idx.size
+	}
+	depth := bits.Len(uint(k)) - 1
+	height := bits.Len(uint(idx.size)) - 1
+	// Position in the perfect tree, then skip the missing leaves before it.
+	pos := (k-1<<depth)*2 + 1
+	pos = pos<<(height-depth) - 1
+	leaves := idx.size - (1<<height - 1)
+	if pos > leaves*2 {
+		pos -= (pos - leaves*2 + 1) / 2
+	}
+	return pos
+}
+
+// descend walks down the tree from root to leaf, turning right when x should
+// be after the node, then returns the node where it turned left last time.
+// It returns 0 if it never turned left.
+func (idx *SearchIndex[E]) descend(x E, upper bool) int {
+	tree, n := idx.tree, idx.size
+	shift := idx.prefetchDepth
+	touch := byte(0)
+	k := 1
+	if upper {
+		for k <= n {
+			if p := k << shift; p < len(tree) {
+				touch += *(*byte)(unsafe.Pointer(&tree[p]))
+			}
+			right := 0
+			if !cmp.Less(x, tree[k]) {
+				right = 1
+			}
+			k = k*2 + right
+		}
+	} else {
+		for k <= n {
+			if p := k << shift; p < len(tree) {
+				touch += *(*byte)(unsafe.Pointer(&tree[p]))
+			}
+			right := 0
+			if cmp.Less(tree[k], x) {
+				right = 1
+			}
+			k = k*2 + right
+		}
+	}
+	k |= int(touch & touchMask)
+	return k >> (bits.TrailingZeros(^uint(k)) + 1)
+}
+
+// touchMask is always zero, it makes touching memory in descend, which works
+// as prefetching, not removable by compiler.
+var touchMask byte
+
+// LowerBound returns the position of the first element not less than x,
+// or Len() if there is no such element.
+func (idx *SearchIndex[E]) LowerBound(x E) int {
+	return idx.rank(idx.descend(x, false))
+}
+
+// UpperBound returns the position of the first element greater than x,
+// or Len() if there is no such element.
+func (idx *SearchIndex[E]) UpperBound(x E) int {
+	return idx.rank(idx.descend(x, true))
+}
+
+// Find searches for x like BinarySearch, returns the position where x is
+// found, or the position where x would appear in the sort order; it also
+// returns a bool saying whether x is really found.
+func (idx *SearchIndex[E]) Find(x E) (int, bool) {
+	k := idx.descend(x, false)
+	return idx.rank(k), k != 0 && !cmp.Less(x, idx.tree[k])
+}
+
+// Range returns positions [lo, hi) of elements in [a, b).
+func (idx *SearchIndex[E]) Range(a, b E) (lo, hi int) {
+	lo = idx.LowerBound(a)
+	if !cmp.Less(a, b) {
+		return lo, lo
+	}
+	return lo, idx.LowerBound(b)
+}
@@ -0,0 +1,76 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slices
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestSearchIndex(t *testing.T) {
+	for n := 0; n < 300; n++ {
+		sorted := make([]int, n)
+		for i := range sorted {
+			sorted[i] = rand.Intn(n/2+1) * 2
+		}
+		Sort(sorted)
+		idx := NewSearchIndex(sorted)
+		if idx.Len() != n {
+			t.Fatalf("Len got %d, want %d", idx.Len(), n)
+		}
+		for x := -1; x <= n+2; x++ {
+			lo := sort.SearchInts(sorted, x)
+			hi := sort.SearchInts(sorted, x+1)
+			if got := idx.LowerBound(x); got != lo {
+				t.Fatalf("LowerBound(%d) got %d, want %d on %d ints", x, got, lo, n)
+			}
+			if got := idx.UpperBound(x); got != hi {
+				t.Fatalf("UpperBound(%d) got %d, want %d on %d ints", x, got, hi, n)
+			}
+			pos, found := idx.Find(x)
+			if pos != lo || found != (lo < hi) {
+				t.Fatalf("Find(%d) got (%d, %v) on %d ints", x, pos, found, n)
+			}
+			if a, b := idx.Range(x, x+3); a != lo || b != sort.SearchInts(sorted, x+3) {
+				t.Fatalf("Range(%d, %d) got [%d, %d) on %d ints", x, x+3, a, b, n)
+			}
+		}
+		if a, b := idx.Range(3, 1); a != b {
+			t.Fatalf("Range(3, 1) got [%d, %d)", a, b)
+		}
+	}
+}
+
+func TestSearchIndexTypes(t *testing.T) {
+	floats := []float64{math.NaN(), math.Inf(-1), -1, 0, 0.5, 0.5, 2, math.Inf(1)}
+	fidx := NewSearchIndex(floats)
+	for i, v := range floats {
+		pos, found := fidx.Find(v)
+		want, _ := BinarySearch(floats, v)
+		if !found || pos != want || pos > i {
+			t.Errorf("Find(%v) got (%d, %v)", v, pos, found)
+		}
+	}
+	if pos, found := fidx.Find(1); found || pos != 6 {
+		t.Errorf("Find(1) got (%d, %v)", pos, found)
+	}
+
+	strs := Clone(strs[:])
+	Sort(strs)
+	sidx := NewSearchIndex(strs)
+	for i, v := range strs {
+		if pos, found := sidx.Find(v); !found || strs[pos] != v || pos > i {
+			t.Errorf("Find(%q) got (%d, %v)", v, pos, found)
+		}
+	}
+
+	bytes := []uint8{1, 3, 3, 3, 200}
+	bidx := NewSearchIndex(bytes)
+	if a, b := bidx.Range(3, 4); a != 1 || b != 4 {
+		t.Errorf("Range(3, 4) got [%d, %d)", a, b)
+	}
+}
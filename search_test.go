@@ -0,0 +1,224 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slices
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"strconv"
+	"testing"
+)
+
+func TestEqualRange(t *testing.T) {
+	data := []int{20, 30, 30, 30, 50, 60, 60, 90}
+	tests := []struct {
+		target int
+		lo, hi int
+	}{
+		{10, 0, 0},
+		{20, 0, 1},
+		{23, 1, 1},
+		{30, 1, 4},
+		{60, 5, 7},
+		{90, 7, 8},
+		{99, 8, 8},
+	}
+	byRef := Order[int]{
+		RefLess: func(a, b *int) bool {
+			return *a < *b
+		},
+	}
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.target), func(t *testing.T) {
+			if pos := LowerBound(data, tt.target); pos != tt.lo {
+				t.Errorf("LowerBound got %d, want %d", pos, tt.lo)
+			}
+			if pos := UpperBound(data, tt.target); pos != tt.hi {
+				t.Errorf("UpperBound got %d, want %d", pos, tt.hi)
+			}
+			if lo, hi := EqualRange(data, tt.target); lo != tt.lo || hi != tt.hi {
+				t.Errorf("EqualRange got [%d, %d), want [%d, %d)", lo, hi, tt.lo, tt.hi)
+			}
+			for _, od := range []*Order[int]{&intOrder, &intCompareOrder, &byRef} {
+				if pos := od.LowerBound(data, tt.target); pos != tt.lo {
+					t.Errorf("Order.LowerBound got %d, want %d", pos, tt.lo)
+				}
+				if pos := od.UpperBound(data, tt.target); pos != tt.hi {
+					t.Errorf("Order.UpperBound got %d, want %d", pos, tt.hi)
+				}
+				if lo, hi := od.EqualRange(data, tt.target); lo != tt.lo || hi != tt.hi {
+					t.Errorf("Order.EqualRange got [%d, %d), want [%d, %d)", lo, hi, tt.lo, tt.hi)
+				}
+			}
+		})
+	}
+
+	floats := []float64{math.NaN(), math.NaN(), -0.25, 0.0, 0.0, 1.4}
+	if lo, hi := EqualRange(floats, math.NaN()); lo != 0 || hi != 2 {
+		t.Errorf("EqualRange(NaN) got [%d, %d)", lo, hi)
+	}
+	if lo, hi := EqualRange(floats, math.Copysign(0, -1)); lo != 3 || hi != 5 {
+		t.Errorf("EqualRange(-0) got [%d, %d)", lo, hi)
+	}
+}
+
+func TestEqualRangeFunc(t *testing.T) {
+	type user struct {
+		name string
+		age  int
+	}
+	data := []user{{"a", 18}, {"b", 20}, {"c", 20}, {"d", 25}, {"e", 30}}
+	byAge := func(u user, age int) int {
+		return cmp.Compare(u.age, age)
+	}
+	tests := []struct {
+		target int
+		lo, hi int
+	}{
+		{10, 0, 0},
+		{18, 0, 1},
+		{20, 1, 3},
+		{22, 3, 3},
+		{30, 4, 5},
+		{40, 5, 5},
+	}
+	for _, tt := range tests {
+		if pos := LowerBoundFunc(data, tt.target, byAge); pos != tt.lo {
+			t.Errorf("LowerBoundFunc(%d) got %d, want %d", tt.target, pos, tt.lo)
+		}
+		if pos := UpperBoundFunc(data, tt.target, byAge); pos != tt.hi {
+			t.Errorf("UpperBoundFunc(%d) got %d, want %d", tt.target, pos, tt.hi)
+		}
+		if lo, hi := EqualRangeFunc(data, tt.target, byAge); lo != tt.lo || hi != tt.hi {
+			t.Errorf("EqualRangeFunc(%d) got [%d, %d), want [%d, %d)", tt.target, lo, hi, tt.lo, tt.hi)
+		}
+	}
+}
+
+func TestInterpolationSearchInts(t *testing.T) {
+	data := []int{20, 30, 40, 50, 60, 70, 80, 90}
+	tests := []struct {
+		target    int
+		wantPos   int
+		wantFound bool
+	}{
+		{20, 0, true},
+		{23, 1, false},
+		{43, 3, false},
+		{80, 6, true},
+	}
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.target), func(t *testing.T) {
+			pos, found := InterpolationSearch(data, tt.target)
+			if pos != tt.wantPos || found != tt.wantFound {
+				t.Errorf("InterpolationSearch got (%v, %v), want (%v, %v)", pos, found, tt.wantPos, tt.wantFound)
+			}
+			for hint := -1; hint <= len(data)+1; hint++ {
+				pos, found = GallopSearch(data, tt.target, hint)
+				if pos != tt.wantPos || found != tt.wantFound {
+					t.Errorf("GallopSearch from %d got (%v, %v), want (%v, %v)", hint, pos, found, tt.wantPos, tt.wantFound)
+				}
+				for _, od := range []*Order[int]{&intOrder, &intCompareOrder} {
+					pos, found = od.GallopSearch(data, tt.target, hint)
+					if pos != tt.wantPos || found != tt.wantFound {
+						t.Errorf("Order.GallopSearch from %d got (%v, %v), want (%v, %v)", hint, pos, found, tt.wantPos, tt.wantFound)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestInterpolationSearchFloats(t *testing.T) {
+	data := []float64{math.NaN(), -0.25, 0.0, 1.4}
+	tests := []struct {
+		target    float64
+		wantPos   int
+		wantFound bool
+	}{
+		{math.NaN(), 0, true},
+		{math.Inf(-1), 1, false},
+		{-0.25, 1, true},
+		{0.0, 2, true},
+		{1.4, 3, true},
+		{1.5, 4, false},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%v", tt.target), func(t *testing.T) {
+			pos, found := InterpolationSearch(data, tt.target)
+			if pos != tt.wantPos || found != tt.wantFound {
+				t.Errorf("InterpolationSearch got (%v, %v), want (%v, %v)", pos, found, tt.wantPos, tt.wantFound)
+			}
+			for hint := 0; hint <= len(data); hint++ {
+				pos, found = GallopSearch(data, tt.target, hint)
+				if pos != tt.wantPos || found != tt.wantFound {
+					t.Errorf("GallopSearch from %d got (%v, %v), want (%v, %v)", hint, pos, found, tt.wantPos, tt.wantFound)
+				}
+			}
+		})
+	}
+}
+
+func TestInterpolationSearchDistributions(t *testing.T) {
+	n := 10000
+	gens := map[string]func(i int) float64{
+		"uniform":     func(i int) float64 { return float64(i) * 3 },
+		"duplicates":  func(i int) float64 { return float64(i / 100) },
+		"exponential": func(i int) float64 { return math.Exp(float64(i) / 100) },
+		"infinity": func(i int) float64 {
+			if i > n-10 {
+				return math.Inf(1)
+			}
+			return float64(i)
+		},
+		"nan": func(i int) float64 {
+			if i < 10 {
+				return math.NaN()
+			}
+			return float64(i)
+		},
+	}
+	for name, gen := range gens {
+		t.Run(name, func(t *testing.T) {
+			data := make([]float64, n)
+			for i := range data {
+				data[i] = gen(i)
+			}
+			for i := -1; i <= n; i += 7 {
+				var target float64
+				switch {
+				case i < 0:
+					target = math.Inf(-1)
+				case i == n:
+					target = math.Inf(1)
+				case i%2 == 0:
+					target = data[i]
+				default:
+					target = data[i] + 0.5
+				}
+				wantPos, wantFound := BinarySearch(data, target)
+				pos, found := InterpolationSearch(data, target)
+				if pos != wantPos || found != wantFound {
+					t.Fatalf("InterpolationSearch(%v) got (%v, %v), want (%v, %v)", target, pos, found, wantPos, wantFound)
+				}
+				pos, found = GallopSearch(data, target, n/2)
+				if pos != wantPos || found != wantFound {
+					t.Fatalf("GallopSearch(%v) got (%v, %v), want (%v, %v)", target, pos, found, wantPos, wantFound)
+				}
+			}
+		})
+	}
+
+	ints := make([]uint64, n)
+	for i := range ints {
+		ints[i] = uint64(i) << 50
+	}
+	for i := 0; i < n; i += 3 {
+		if pos, found := InterpolationSearch(ints, ints[i]); pos != i || !found {
+			t.Fatalf("InterpolationSearch(%d) got (%v, %v)", ints[i], pos, found)
+		}
+	}
+}
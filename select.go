@@ -0,0 +1,122 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slices
+
+import (
+	"cmp"
+)
+
+// NthElement rearranges list so that list[k] is the element which would be
+// there if list is sorted. Elements before list[k] are not greater than it,
+// and elements after list[k] are not less than it.
+// It panics if k is out of range.
+func NthElement[E cmp.Ordered](list []E, k int) {
+	if k < 0 || k >= len(list) {
+		panic("slices.NthElement: index out of range")
+	}
+	partlySelect(list, k+1)
+}
+
+// Median returns the lower median of list, which is partially reordered.
+// It panics if list is empty.
+func Median[E cmp.Ordered](list []E) E {
+	if len(list) < 1 {
+		panic("slices.Median: empty list")
+	}
+	k := (len(list) - 1) / 2
+	partlySelect(list, k+1)
+	return list[k]
+}
+
+// Quantiles returns the elements at quantiles qs of list in one pass, which
+// partially reorders list. The q quantile is the element at index
+// int(q*(len(list)-1)) if list is sorted, without interpolation.
+// It panics if list is empty or any q is not in [0, 1].
+func Quantiles[E cmp.Ordered](list []E, qs ...float64) []E {
+	pos, ks := quantilePositions(len(list), qs)
+	multiSelect(list, ks)
+	return pickQuantiles(list, pos)
+}
+
+// The general version of NthElement.
+func (od *Order[E]) NthElement(list []E, k int) {
+	if k < 0 || k >= len(list) {
+		panic("slices.NthElement: index out of range")
+	}
+	switch od.kind() {
+	case kindLess:
+		lessFunc[E](od.Less).partlySelect(list, k+1)
+	case kindRefLess:
+		refLessFunc[E](od.RefLess).partlySelect(list, k+1)
+	case kindCompare:
+		compareFunc[E](od.Compare).partlySelect(list, k+1)
+	default:
+		refCompareFunc[E](od.RefCompare).partlySelect(list, k+1)
+	}
+}
+
+// The general version of Median.
+func (od *Order[E]) Median(list []E) E {
+	if len(list) < 1 {
+		panic("slices.Median: empty list")
+	}
+	k := (len(list) - 1) / 2
+	switch od.kind() {
+	case kindLess:
+		lessFunc[E](od.Less).partlySelect(list, k+1)
+	case kindRefLess:
+		refLessFunc[E](od.RefLess).partlySelect(list, k+1)
+	case kindCompare:
+		compareFunc[E](od.Compare).partlySelect(list, k+1)
+	default:
+		refCompareFunc[E](od.RefCompare).partlySelect(list, k+1)
+	}
+	return list[k]
+}
+
+// The general version of Quantiles.
+func (od *Order[E]) Quantiles(list []E, qs ...float64) []E {
+	pos, ks := quantilePositions(len(list), qs)
+	switch od.kind() {
+	case kindLess:
+		lessFunc[E](od.Less).multiSelect(list, ks)
+	case kindRefLess:
+		refLessFunc[E](od.RefLess).multiSelect(list, ks)
+	case kindCompare:
+		compareFunc[E](od.Compare).multiSelect(list, ks)
+	default:
+		refCompareFunc[E](od.RefCompare).multiSelect(list, ks)
+	}
+	return pickQuantiles(list, pos)
+}
+
+// quantilePositions returns the position for each quantile, and the
+// ascending unique positions to select.
+func quantilePositions(size int, qs []float64) (pos, ks []int) {
+	if len(qs) == 0 {
+		return nil, nil
+	}
+	if size < 1 {
+		panic("slices.Quantiles: empty list")
+	}
+	pos = make([]int, len(qs))
+	for i, q := range qs {
+		if !(q >= 0 && q <= 1) {
+			panic("slices.Quantiles: quantile out of range")
+		}
+		pos[i] = int(q * float64(size-1))
+	}
+	ks = Clone(pos)
+	Sort(ks)
+	return pos, Compact(ks)
+}
+
+func pickQuantiles[E any](list []E, pos []int) []E {
+	out := make([]E, len(pos))
+	for i, p := range pos {
+		out[i] = list[p]
+	}
+	return out
+}
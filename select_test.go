@@ -0,0 +1,116 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slices
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func checkNthElement(t *testing.T, list, sorted []int, k int) {
+	t.Helper()
+	if list[k] != sorted[k] {
+		t.Fatalf("element %d got %d, want %d", k, list[k], sorted[k])
+	}
+	for i := 0; i < k; i++ {
+		if list[i] > list[k] {
+			t.Fatalf("element %d is greater than element %d", i, k)
+		}
+	}
+	for i := k + 1; i < len(list); i++ {
+		if list[i] < list[k] {
+			t.Fatalf("element %d is less than element %d", i, k)
+		}
+	}
+}
+
+func TestNthElement(t *testing.T) {
+	for _, n := range []int{1, 2, 10, 100, 1000} {
+		for _, m := range []int{3, n} {
+			data := make([]int, n)
+			for i := range data {
+				data[i] = rand.Intn(m)
+			}
+			sorted := Clone(data)
+			Sort(sorted)
+			for _, k := range []int{0, n / 3, n / 2, n - 1} {
+				list := Clone(data)
+				NthElement(list, k)
+				checkNthElement(t, list, sorted, k)
+
+				list = Clone(data)
+				intCompareOrder.NthElement(list, k)
+				checkNthElement(t, list, sorted, k)
+			}
+		}
+	}
+}
+
+func TestMedian(t *testing.T) {
+	data := Clone(ints[:])
+	sorted := Clone(data)
+	Sort(sorted)
+	if got, want := Median(data), sorted[(len(sorted)-1)/2]; got != want {
+		t.Errorf("Median got %d, want %d", got, want)
+	}
+	data = Clone(ints[:len(ints)-1])
+	sorted = Clone(data)
+	Sort(sorted)
+	if got, want := intOrder.Median(data), sorted[(len(sorted)-1)/2]; got != want {
+		t.Errorf("Order.Median got %d, want %d", got, want)
+	}
+}
+
+func TestQuantiles(t *testing.T) {
+	n := 100001
+	data := make([]int, n)
+	for i := range data {
+		data[i] = rand.Intn(n)
+	}
+	sorted := Clone(data)
+	Sort(sorted)
+	qs := []float64{0.99, 0.5, 0.9, 0, 1, 0.5}
+	want := make([]int, len(qs))
+	for i, q := range qs {
+		want[i] = sorted[int(q*float64(n-1))]
+	}
+	if got := Quantiles(Clone(data), qs...); !Equal(got, want) {
+		t.Errorf("Quantiles got %v, want %v", got, want)
+	}
+	if got := intCompareOrder.Quantiles(Clone(data), qs...); !Equal(got, want) {
+		t.Errorf("Order.Quantiles got %v, want %v", got, want)
+	}
+	if got := Quantiles([]int(nil)); len(got) != 0 {
+		t.Errorf("Quantiles got %v, want empty", got)
+	}
+
+	// Many quantiles lead to a full sort.
+	qs = make([]float64, 1001)
+	for i := range qs {
+		qs[i] = float64(i) / 1000
+	}
+	list := Clone(data[:1001])
+	Quantiles(list, qs...)
+	if !IsSorted(list) {
+		t.Errorf("Quantiles didn't sort with all positions")
+	}
+}
+
+func TestSelectPanics(t *testing.T) {
+	expectPanic := func(name string, fn func()) {
+		t.Helper()
+		defer func() {
+			if recover() == nil {
+				t.Errorf("%s didn't panic", name)
+			}
+		}()
+		fn()
+	}
+	expectPanic("NthElement", func() { NthElement(Clone(ints[:]), len(ints)) })
+	expectPanic("NthElement", func() { intOrder.NthElement(Clone(ints[:]), -1) })
+	expectPanic("Median", func() { Median([]int{}) })
+	expectPanic("Quantiles", func() { Quantiles([]int{}, 0.5) })
+	expectPanic("Quantiles", func() { Quantiles([]int{1}, 1.5) })
+}
@@ -0,0 +1,131 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slices
+
+import (
+	"cmp"
+)
+
+type setOp uint8
+
+const (
+	setUnion setOp = iota
+	setIntersect
+	setDifference
+	setSymmetricDifference
+)
+
+// Union appends the union of sorted a and b to dst, and returns the extended
+// slice. When multi is set, a and b are treated as multisets, and an element
+// appears max(m, n) times in the result if it appears m times in a and n times
+// in b. Otherwise every element appears only once.
+// Elements from a are preferred when equal elements are chosen.
+// dst should not overlap with a or b.
+func Union[E cmp.Ordered](dst, a, b []E, multi bool) []E {
+	return setOperate(dst, a, b, setUnion, multi)
+}
+
+// Intersect appends the intersection of sorted a and b to dst, and returns
+// the extended slice. When multi is set, an element appears min(m, n) times
+// in the result if it appears m times in a and n times in b. Otherwise every
+// element appears only once.
+// Elements from a are chosen. dst should not overlap with a or b.
+func Intersect[E cmp.Ordered](dst, a, b []E, multi bool) []E {
+	return setOperate(dst, a, b, setIntersect, multi)
+}
+
+// Difference appends elements of sorted a which are not in sorted b to dst,
+// and returns the extended slice. When multi is set, an element appears m-n
+// times in the result if it appears m times in a and n times in b. Otherwise
+// every element appears only once.
+// dst should not overlap with a or b.
+func Difference[E cmp.Ordered](dst, a, b []E, multi bool) []E {
+	return setOperate(dst, a, b, setDifference, multi)
+}
+
+// SymmetricDifference appends elements in only one of sorted a and b to dst,
+// and returns the extended slice. When multi is set, an element appears |m-n|
+// times in the result if it appears m times in a and n times in b. Otherwise
+// every element appears only once.
+// dst should not overlap with a or b.
+func SymmetricDifference[E cmp.Ordered](dst, a, b []E, multi bool) []E {
+	return setOperate(dst, a, b, setSymmetricDifference, multi)
+}
+
+// IsSubset reports whether every element of sorted a is in sorted b. When
+// multi is set, an element should not appear more times in a than in b.
+func IsSubset[E cmp.Ordered](a, b []E, multi bool) bool {
+	return isSubset(a, b, multi)
+}
+
+// The general version of Union.
+func (od *Order[E]) Union(dst, a, b []E, multi bool) []E {
+	switch od.kind() {
+	case kindLess:
+		return lessFunc[E](od.Less).setOperate(dst, a, b, setUnion, multi)
+	case kindRefLess:
+		return refLessFunc[E](od.RefLess).setOperate(dst, a, b, setUnion, multi)
+	case kindCompare:
+		return compareFunc[E](od.Compare).setOperate(dst, a, b, setUnion, multi)
+	default:
+		return refCompareFunc[E](od.RefCompare).setOperate(dst, a, b, setUnion, multi)
+	}
+}
+
+// The general version of Intersect.
+func (od *Order[E]) Intersect(dst, a, b []E, multi bool) []E {
+	switch od.kind() {
+	case kindLess:
+		return lessFunc[E](od.Less).setOperate(dst, a, b, setIntersect, multi)
+	case kindRefLess:
+		return refLessFunc[E](od.RefLess).setOperate(dst, a, b, setIntersect, multi)
+	case kindCompare:
+		return compareFunc[E](od.Compare).setOperate(dst, a, b, setIntersect, multi)
+	default:
+		return refCompareFunc[E](od.RefCompare).setOperate(dst, a, b, setIntersect, multi)
+	}
+}
+
+// The general version of Difference.
+func (od *Order[E]) Difference(dst, a, b []E, multi bool) []E {
+	switch od.kind() {
+	case kindLess:
+		return lessFunc[E](od.Less).setOperate(dst, a, b, setDifference, multi)
+	case kindRefLess:
+		return refLessFunc[E](od.RefLess).setOperate(dst, a, b, setDifference, multi)
+	case kindCompare:
+		return compareFunc[E](od.Compare).setOperate(dst, a, b, setDifference, multi)
+	default:
+		return refCompareFunc[E](od.RefCompare).setOperate(dst, a, b, setDifference, multi)
+	}
+}
+
+// The general version of SymmetricDifference.
+func (od *Order[E]) SymmetricDifference(dst, a, b []E, multi bool) []E {
+	switch od.kind() {
+	case kindLess:
+		return lessFunc[E](od.Less).setOperate(dst, a, b, setSymmetricDifference, multi)
+	case kindRefLess:
+		return refLessFunc[E](od.RefLess).setOperate(dst, a, b, setSymmetricDifference, multi)
+	case kindCompare:
+		return compareFunc[E](od.Compare).setOperate(dst, a, b, setSymmetricDifference, multi)
+	default:
+		return refCompareFunc[E](od.RefCompare).setOperate(dst, a, b, setSymmetricDifference, multi)
+	}
+}
+
+// The general version of IsSubset.
+func (od *Order[E]) IsSubset(a, b []E, multi bool) bool {
+	switch od.kind() {
+	case kindLess:
+		return lessFunc[E](od.Less).isSubset(a, b, multi)
+	case kindRefLess:
+		return refLessFunc[E](od.RefLess).isSubset(a, b, multi)
+	case kindCompare:
+		return compareFunc[E](od.Compare).isSubset(a, b, multi)
+	default:
+		return refCompareFunc[E](od.RefCompare).isSubset(a, b, multi)
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slices
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// setReference works out set operations by counting.
+func setReference(a, b []int, op setOp, multi bool) []int {
+	ca, cb := map[int]int{}, map[int]int{}
+	for _, v := range a {
+		ca[v]++
+	}
+	for _, v := range b {
+		cb[v]++
+	}
+	keys := make([]int, 0, len(ca)+len(cb))
+	for v := range ca {
+		keys = append(keys, v)
+	}
+	for v := range cb {
+		if ca[v] == 0 {
+			keys = append(keys, v)
+		}
+	}
+	Sort(keys)
+	var out []int
+	for _, v := range keys {
+		m, n := ca[v], cb[v]
+		if !multi {
+			m, n = min(m, 1), min(n, 1)
+		}
+		var cnt int
+		switch op {
+		case setUnion:
+			cnt = max(m, n)
+		case setIntersect:
+			cnt = min(m, n)
+		case setDifference:
+			cnt = max(m-n, 0)
+		case setSymmetricDifference:
+			cnt = max(m-n, n-m)
+		}
+		for i := 0; i < cnt; i++ {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func randomSortedInts(n, m int) []int {
+	list := make([]int, n)
+	for i := range list {
+		list[i] = rand.Intn(m)
+	}
+	Sort(list)
+	return list
+}
+
+func TestSetOperations(t *testing.T) {
+	od := Order[int]{
+		RefLess: func(a, b *int) bool {
+			return *a < *b
+		},
+	}
+	ops := []struct {
+		name string
+		op   setOp
+		fn   func(dst, a, b []int, multi bool) []int
+		odFn func(dst, a, b []int, multi bool) []int
+	}{
+		{"Union", setUnion, Union[int], od.Union},
+		{"Intersect", setIntersect, Intersect[int], intCompareOrder.Intersect},
+		{"Difference", setDifference, Difference[int], od.Difference},
+		{"SymmetricDifference", setSymmetricDifference,
+			SymmetricDifference[int], intCompareOrder.SymmetricDifference},
+	}
+	sizes := [][3]int{{0, 0, 10}, {0, 10, 10}, {10, 0, 10}, {100, 100, 50},
+		{100, 100, 1000}, {10, 10000, 20000}, {10000, 10, 20000}, {1000, 3000, 100}}
+	for _, op := range ops {
+		for _, size := range sizes {
+			a, b := randomSortedInts(size[0], size[2]), randomSortedInts(size[1], size[2])
+			for _, multi := range []bool{false, true} {
+				want := setReference(a, b, op.op, multi)
+				got := op.fn([]int{-1}, a, b, multi)
+				if got[0] != -1 || !Equal(got[1:], want) {
+					t.Errorf("%s(multi=%v) mismatch on %v", op.name, multi, size)
+				}
+				if got := op.odFn(nil, a, b, multi); !Equal(got, want) {
+					t.Errorf("Order.%s(multi=%v) mismatch on %v", op.name, multi, size)
+				}
+			}
+		}
+	}
+}
+
+func TestIsSubset(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		a, b := randomSortedInts(rand.Intn(10), 20), randomSortedInts(rand.Intn(100), 20)
+		for _, multi := range []bool{false, true} {
+			want := len(setReference(a, b, setDifference, multi)) == 0
+			if got := IsSubset(a, b, multi); got != want {
+				t.Errorf("IsSubset(%v, %v, %v) got %v", a, b, multi, got)
+			}
+			if got := intOrder.IsSubset(a, b, multi); got != want {
+				t.Errorf("Order.IsSubset(%v, %v, %v) got %v", a, b, multi, got)
+			}
+			if got := intCompareOrder.IsSubset(a, b, multi); got != want {
+				t.Errorf("Order.IsSubset(%v, %v, %v) with Compare got %v", a, b, multi, got)
+			}
+		}
+	}
+	if !IsSubset([]int{1, 1, 2}, []int{1, 2, 3}, false) {
+		t.Errorf("IsSubset failed with duplicates in set mode")
+	}
+	if IsSubset([]int{1, 1, 2}, []int{1, 2, 3}, true) {
+		t.Errorf("IsSubset failed with duplicates in multiset mode")
+	}
+}
+
+func TestSetPreferA(t *testing.T) {
+	a := []intPair{{1, 0}, {2, 0}, {2, 0}, {3, 0}}
+	b := []intPair{{2, 1}, {2, 1}, {2, 1}, {4, 1}}
+	got := intPairOrder.Union(nil, a, b, true)
+	want := []intPair{{1, 0}, {2, 0}, {2, 0}, {2, 1}, {3, 0}, {4, 1}}
+	if !Equal(got, want) {
+		t.Errorf("Union got %v, want %v", got, want)
+	}
+	got = intPairOrder.Intersect(nil, a, b, false)
+	want = []intPair{{2, 0}}
+	if !Equal(got, want) {
+		t.Errorf("Intersect got %v, want %v", got, want)
+	}
+}
@@ -11,7 +11,14 @@ import (
 
 // Sort sorts a slice of any ordered type in ascending order.
 // When sorting floating-point numbers, NaNs are ordered before other values.
+// Radix sort is used for long list of integers or floating-point numbers.
 func Sort[E cmp.Ordered](list []E) {
+	if len(list) >= radixSize {
+		if kind := radixKindOf[E](); kind != radixNone {
+			radixSort(list, kind)
+			return
+		}
+	}
 	if !tryBlockIntroSort(list) {
 		sortFast(list)
 	}
@@ -19,7 +26,15 @@ func Sort[E cmp.Ordered](list []E) {
 
 // SortStableFunc sorts the slice x while keeping the original order of equal
 // elements, using cmp to compare elements.
+// Radix sort is used for long list of integers or floating-point numbers,
+// which needs O(n) size extra memory.
 func SortStable[E cmp.Ordered](list []E) {
+	if len(list) >= radixSize {
+		if kind := radixKindOf[E](); kind != radixNone {
+			radixSortStable(list, kind)
+			return
+		}
+	}
 	sortStable(list, true)
 }
 
@@ -57,58 +72,122 @@ func BinarySearch[E cmp.Ordered](x []E, target E) (int, bool) {
 
 type lessFunc[E any] func(a, b E) bool
 type refLessFunc[E any] func(a, b *E) bool
+type compareFunc[E any] func(a, b E) int
+type refCompareFunc[E any] func(a, b *E) int
 
 // Order record the way of comparison, will never changed by its methods.
 //
 // .Less is a comparison function with value input.
 // .RefLess is a comparison function with pointer input.
+// .Compare is a three-way comparison function with value input.
+// .RefCompare is a three-way comparison function with pointer input.
+// Three-way comparison functions return a negative number when a < b,
+// a positive number when a > b and zero when a == b, like cmp.Compare.
 // At least one of them should be set before use.
-// If both of them are set, they must have the same behavior.
+// If more than one of them are set, they must have the same behavior, and
+// three-way ones are used, which tell equality with one call.
 type Order[E any] struct {
-	Less    func(a, b E) bool
-	RefLess func(a, b *E) bool
+	Less       func(a, b E) bool
+	RefLess    func(a, b *E) bool
+	Compare    func(a, b E) int
+	RefCompare func(a, b *E) int
 }
 
+// sortEvent is reported by the algorithms in sort_ordered.go with observe.
+type sortEvent uint8
+
+const (
+	eventPresorted sortEvent = iota // arg is unused
+	eventPartition                  // arg is the remaining chance
+	eventHeapSort                   // arg is the length of heap sorted list
+	eventSwap                       // arg is the number of swaps
+	eventAlloc                      // arg is the number of allocated elements
+)
+
+// observe does nothing, so it costs nothing. It's replaced with the method
+// of statsOrder in the instrumented specialization.
+func observe[E any](ev sortEvent, arg int) {}
+
 func isSmallUnit[E any]() bool {
 	var elem E
 	var word uintptr
 	return unsafe.Sizeof(elem) <= unsafe.Sizeof(word)*2
 }
 
+// algoKind tells which comparison function of Order is used.
+type algoKind uint8
+
+const (
+	kindLess algoKind = iota
+	kindRefLess
+	kindCompare
+	kindRefCompare
+)
+
+func (k algoKind) byRef() bool {
+	return k == kindRefLess || k == kindRefCompare
+}
+
+// kind picks a comparison function, three-way ones are preferred.
+// The pointer version is chosen when value version is missing or the element
+// is not small.
+func (od *Order[E]) kind() algoKind {
+	switch {
+	case od.Compare != nil || od.RefCompare != nil:
+		if od.Compare == nil || (od.RefCompare != nil && !isSmallUnit[E]()) {
+			return kindRefCompare
+		}
+		return kindCompare
+	case od.Less != nil || od.RefLess != nil:
+		if od.Less == nil || (od.RefLess != nil && !isSmallUnit[E]()) {
+			return kindRefLess
+		}
+		return kindLess
+	default:
+		panic("uninitialized Order")
+	}
+}
+
 // The general version of BinarySearch.
 func (od *Order[E]) BinarySearch(list []E, target E) (int, bool) {
-	if od.RefLess == nil {
-		if od.Less == nil {
-			panic("uninitialized Order")
-		}
-	} else if od.Less == nil || !isSmallUnit[E]() {
+	switch od.kind() {
+	case kindLess:
+		return lessFunc[E](od.Less).binarySearch(list, target)
+	case kindRefLess:
 		return refLessFunc[E](od.RefLess).binarySearch(list, target)
+	case kindCompare:
+		return compareFunc[E](od.Compare).binarySearch(list, target)
+	default:
+		return refCompareFunc[E](od.RefCompare).binarySearch(list, target)
 	}
-	return lessFunc[E](od.Less).binarySearch(list, target)
 }
 
 // The general version of Min.
 func (od *Order[E]) Min(list []E) E {
-	if od.RefLess == nil {
-		if od.Less == nil {
-			panic("uninitialized Order")
-		}
-	} else if od.Less == nil || !isSmallUnit[E]() {
+	switch od.kind() {
+	case kindLess:
+		return lessFunc[E](od.Less).findMin(list)
+	case kindRefLess:
 		return refLessFunc[E](od.RefLess).findMin(list)
+	case kindCompare:
+		return compareFunc[E](od.Compare).findMin(list)
+	default:
+		return refCompareFunc[E](od.RefCompare).findMin(list)
 	}
-	return lessFunc[E](od.Less).findMin(list)
 }
 
 // The general version of Max.
 func (od *Order[E]) Max(list []E) E {
-	if od.RefLess == nil {
-		if od.Less == nil {
-			panic("uninitialized Order")
-		}
-	} else if od.Less == nil || !isSmallUnit[E]() {
+	switch od.kind() {
+	case kindLess:
+		return lessFunc[E](od.Less).findMax(list)
+	case kindRefLess:
 		return refLessFunc[E](od.RefLess).findMax(list)
+	case kindCompare:
+		return compareFunc[E](od.Compare).findMax(list)
+	default:
+		return refCompareFunc[E](od.RefCompare).findMax(list)
 	}
-	return lessFunc[E](od.Less).findMax(list)
 }
 
 // The general version of IsSorted.
@@ -116,14 +195,16 @@ func (od *Order[E]) IsSorted(list []E) bool {
 	if len(list) < 2 {
 		return true
 	}
-	if od.RefLess == nil {
-		if od.Less == nil {
-			panic("uninitialized Order")
-		}
-	} else if od.Less == nil || !isSmallUnit[E]() {
+	switch od.kind() {
+	case kindLess:
+		return lessFunc[E](od.Less).isSorted(list)
+	case kindRefLess:
 		return refLessFunc[E](od.RefLess).isSorted(list)
+	case kindCompare:
+		return compareFunc[E](od.Compare).isSorted(list)
+	default:
+		return refCompareFunc[E](od.RefCompare).isSorted(list)
 	}
-	return lessFunc[E](od.Less).isSorted(list)
 }
 
 // The general version of Sort.
@@ -143,15 +224,16 @@ func (od *Order[E]) PartlySort(list []E, k int) {
 	if len(list) < 2 || k <= 0 {
 		return
 	}
-	if od.RefLess == nil {
-		if od.Less == nil {
-			panic("uninitialized Order")
-		}
-	} else if od.Less == nil || !isSmallUnit[E]() {
+	switch od.kind() {
+	case kindLess:
+		lessFunc[E](od.Less).partlySort(list, k)
+	case kindRefLess:
 		refLessFunc[E](od.RefLess).partlySort(list, k)
-		return
+	case kindCompare:
+		compareFunc[E](od.Compare).partlySort(list, k)
+	default:
+		refCompareFunc[E](od.RefCompare).partlySort(list, k)
 	}
-	lessFunc[E](od.Less).partlySort(list, k)
 }
 
 var cacheInfo = struct {
@@ -162,6 +244,12 @@ var cacheInfo = struct {
 	available: 256 * 1024, //available bytes for sort
 }
 
+var memInfo = struct {
+	total int
+}{
+	total: 1024 * 1024 * 1024, //physical memory size
+}
+
 // The general sort function.
 // Guarantee stability when stable flag is set.
 // Avoid allocating O(n) size extra memory when inplace flag is set.
@@ -169,11 +257,29 @@ func (od *Order[E]) SortWithOption(list []E, stable, inplace bool) {
 	if len(list) < 2 {
 		return
 	}
-	if od.RefLess == nil {
-		if od.Less == nil {
-			panic("uninitialized Order")
-		}
-	} else if od.Less == nil || !isSmallUnit[E]() {
+	switch od.strategy(list, stable, inplace, nil) {
+	case StrategyIntroSort:
+		od.sortFast(list)
+	case StrategyMergeSort:
+		od.sortStable(list, false)
+	case StrategyInplaceMergeSort:
+		od.sortStable(list, true)
+	default:
+		// sort by pointer list, fast in cache
+		ref := refsOf(list)
+		od.sortRefs(ref, stable, nil)
+		reorder(list, ref)
+	}
+}
+
+// strategy decides how SortWithOption sorts list, which has 2 elements at
+// least. The decisions are reported to stats when it's not nil.
+func (od *Order[E]) strategy(list []E, stable, inplace bool, stats *Stats) Strategy {
+	byRef := od.kind().byRef()
+	if stats != nil {
+		stats.ByRef = byRef
+	}
+	if byRef {
 		elemSize := int(unsafe.Sizeof(list[0]))
 		wordSize := int(unsafe.Sizeof(uintptr(0)))
 		footprint := elemSize
@@ -185,39 +291,106 @@ func (od *Order[E]) SortWithOption(list []E, stable, inplace bool) {
 		// random access is expensive for big data
 		noRefSort := elemSize*len(list) < 1024 ||
 			footprint*len(list) > cacheInfo.available
+		if stats != nil {
+			stats.NoRefSort = noRefSort
+		}
 		if stable {
 			if inplace {
-				refLessFunc[E](od.RefLess).sortStable(list, true)
-				return
+				return StrategyInplaceMergeSort
 			}
 			if noRefSort {
-				refLessFunc[E](od.RefLess).sortStable(list, false)
-				return
+				return StrategyMergeSort
 			}
+			return StrategyRefMergeSort
 		} else if elemSize <= wordSize*4 || noRefSort || inplace {
 			//slower than ref mode, but no extra allocation
-			refLessFunc[E](od.RefLess).sortFast(list)
-			return
+			return StrategyIntroSort
+		}
+		return StrategyRefIntroSort
+	}
+	if stable {
+		if inplace {
+			return StrategyInplaceMergeSort
 		}
+		return StrategyMergeSort
+	}
+	return StrategyIntroSort
+}
 
-		// sort by pointer list, fast in cache
-		ref := make([]*E, len(list))
-		for i := 0; i < len(list); i++ {
-			ref[i] = &list[i]
+func (od *Order[E]) sortFast(list []E) {
+	switch od.kind() {
+	case kindLess:
+		lessFunc[E](od.Less).sortFast(list)
+	case kindRefLess:
+		refLessFunc[E](od.RefLess).sortFast(list)
+	case kindCompare:
+		compareFunc[E](od.Compare).sortFast(list)
+	default:
+		refCompareFunc[E](od.RefCompare).sortFast(list)
+	}
+}
+
+func (od *Order[E]) sortStable(list []E, inplace bool) {
+	switch od.kind() {
+	case kindLess:
+		lessFunc[E](od.Less).sortStable(list, inplace)
+	case kindRefLess:
+		refLessFunc[E](od.RefLess).sortStable(list, inplace)
+	case kindCompare:
+		compareFunc[E](od.Compare).sortStable(list, inplace)
+	default:
+		refCompareFunc[E](od.RefCompare).sortStable(list, inplace)
+	}
+}
+
+// sortRefs sorts the pointers to elements. The value version comparison is
+// wrapped when it's chosen. It checks done periodically when done is not nil,
+// and returns false when cancelled.
+func (od *Order[E]) sortRefs(ref []*E, stable bool, done cancelSignal) bool {
+	switch kind := od.kind(); kind {
+	case kindLess, kindRefLess:
+		algo := lessFunc[*E](od.RefLess)
+		if kind == kindLess {
+			less := od.Less
+			algo = func(a, b *E) bool { return less(*a, *b) }
 		}
-		if stable {
-			lessFunc[*E](od.RefLess).sortStable(ref, false)
-		} else {
-			lessFunc[*E](od.RefLess).sortFast(ref)
+		switch {
+		case done != nil && stable:
+			return algo.sortStableCancel(ref, done)
+		case done != nil:
+			return algo.sortFastCancel(ref, done)
+		case stable:
+			algo.sortStable(ref, false)
+		default:
+			algo.sortFast(ref)
+		}
+	default:
+		algo := compareFunc[*E](od.RefCompare)
+		if kind == kindCompare {
+			compare := od.Compare
+			algo = func(a, b *E) int { return compare(*a, *b) }
+		}
+		switch {
+		case done != nil && stable:
+			return algo.sortStableCancel(ref, done)
+		case done != nil:
+			return algo.sortFastCancel(ref, done)
+		case stable:
+			algo.sortStable(ref, false)
+		default:
+			algo.sortFast(ref)
 		}
-		reorder(list, ref)
-		return
 	}
-	if stable {
-		lessFunc[E](od.Less).sortStable(list, inplace)
-	} else {
-		lessFunc[E](od.Less).sortFast(list)
+	return true
+}
+
+// refsOf returns pointers to elements of list.
+func refsOf[E any](list []E) []*E {
+	ref := make([]*E, len(list))
+	for i := 0; i < len(list); i++ {
+		ref[i] = &list[i]
 	}
+	return ref
 }
 
 func ptrDiff[T any](a, b *T) int {
@@ -5,6 +5,8 @@
 package slices
 
 import (
+	"cmp"
+	"container/heap"
 	"fmt"
 	"math"
 	"math/rand"
@@ -117,6 +119,16 @@ func BenchmarkIntStd(b *testing.B) {
 	benchmarkInt(b, std.Sort[[]int, int])
 }
 
+func BenchmarkIntRadix(b *testing.B) {
+	benchmarkInt(b, RadixSort[int])
+}
+
+func BenchmarkIntParallel(b *testing.B) {
+	benchmarkInt(b, func(list []int) {
+		ParallelSort(list, 0)
+	})
+}
+
 func benchmarkHybrid(b *testing.B, sort func([]int)) {
 	n := 10000
 	for _, m := range []int{5, 10, 20, 30, 50} {
@@ -208,6 +220,10 @@ func BenchmarkFloatStd(b *testing.B) {
 	benchmarkFloat(b, std.Sort[[]float64, float64])
 }
 
+func BenchmarkFloatRadix(b *testing.B) {
+	benchmarkFloat(b, RadixSort[float64])
+}
+
 func benchmarkString(b *testing.B, sort func([]string)) {
 	for _, sc := range level {
 		b.Run(sc.name, func(b *testing.B) {
@@ -264,6 +280,14 @@ func BenchmarkStructNew(b *testing.B) {
 	})
 }
 
+func BenchmarkStructByKey(b *testing.B) {
+	benchmarkStruct(b, func(list []smallObject) {
+		SortByKey(list, func(o *smallObject) int {
+			return o.val
+		})
+	})
+}
+
 func BenchmarkStructStd(b *testing.B) {
 	benchmarkStruct(b, func(list []smallObject) {
 		std.SortFunc[[]smallObject, smallObject](list, func(a, b smallObject) int {
@@ -357,3 +381,169 @@ func BenchmarkPointerStd(b *testing.B) {
 		})
 	})
 }
+
+func benchmarkPaired(b *testing.B, sort func([]uint64, []float32)) {
+	for _, sc := range level {
+		b.Run(sc.name, func(b *testing.B) {
+			b.StopTimer()
+			rand.Seed(0)
+			keys := make([]uint64, sc.size)
+			vals := make([]float32, sc.size)
+			for i := 0; i < b.N; i++ {
+				for j := 0; j < sc.size; j++ {
+					keys[j] = rand.Uint64()
+				}
+				b.StartTimer()
+				sort(keys, vals)
+				b.StopTimer()
+			}
+		})
+	}
+}
+
+func BenchmarkPairedNew(b *testing.B) {
+	benchmarkPaired(b, SortPaired[uint64, float32])
+}
+
+func BenchmarkPairedArgSort(b *testing.B) {
+	benchmarkPaired(b, func(keys []uint64, vals []float32) {
+		perm := ArgSort(keys)
+		ApplyPermutation(keys, perm)
+		ApplyPermutation(vals, perm)
+	})
+}
+
+var searchLevel = []sizeClass{
+	sizeClass{"1K", 1000},
+	sizeClass{"10K", 10_000},
+	sizeClass{"100K", 100_000},
+	sizeClass{"1M", 1000_000},
+	sizeClass{"10M", 10_000_000},
+	sizeClass{"100M", 100_000_000},
+}
+
+func benchmarkSearch(b *testing.B, build func([]int32) func(int32) int) {
+	for _, sc := range searchLevel {
+		b.Run(sc.name, func(b *testing.B) {
+			if testing.Short() && sc.size > 1000_000 {
+				b.Skip("skipping in short mode")
+			}
+			rand.Seed(0)
+			list := make([]int32, sc.size)
+			for i := 0; i < sc.size; i++ {
+				list[i] = int32(i * 2)
+			}
+			search := build(list)
+			keys := make([]int32, 64*1024)
+			for i := 0; i < len(keys); i++ {
+				keys[i] = int32(rand.Intn(sc.size * 2))
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				search(keys[i%len(keys)])
+			}
+		})
+	}
+}
+
+func BenchmarkSearchBinary(b *testing.B) {
+	benchmarkSearch(b, func(list []int32) func(int32) int {
+		return func(x int32) int {
+			pos, _ := BinarySearch(list, x)
+			return pos
+		}
+	})
+}
+
+func BenchmarkSearchIndex(b *testing.B) {
+	benchmarkSearch(b, func(list []int32) func(int32) int {
+		idx := NewSearchIndex(list)
+		return idx.LowerBound
+	})
+}
+
+func BenchmarkHybridStableNew(b *testing.B) {
+	benchmarkHybrid(b, SortStable[int])
+}
+
+func BenchmarkHybridStableStd(b *testing.B) {
+	benchmarkHybrid(b, func(list []int) {
+		std.SortStableFunc(list, cmp.Compare[int])
+	})
+}
+
+type stdIntHeap []int
+
+func (h stdIntHeap) Len() int           { return len(h) }
+func (h stdIntHeap) Less(i, j int) bool { return h[i] < h[j] }
+func (h stdIntHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *stdIntHeap) Push(x any)        { *h = append(*h, x.(int)) }
+func (h *stdIntHeap) Pop() any {
+	x := (*h)[len(*h)-1]
+	*h = (*h)[:len(*h)-1]
+	return x
+}
+
+func BenchmarkHeap(b *testing.B) {
+	n := 100000
+	data := make([]int, n)
+	rand.Seed(0)
+	randomInts(data)
+	for _, arity := range []int{2, 4, 8} {
+		b.Run(fmt.Sprintf("%d-ary", arity), func(b *testing.B) {
+			h := NewHeap[int](arity)
+			for i := 0; i < b.N; i++ {
+				for _, v := range data {
+					h.Push(v)
+				}
+				for h.Len() != 0 {
+					h.Pop()
+				}
+			}
+		})
+	}
+	b.Run("Std", func(b *testing.B) {
+		h := &stdIntHeap{}
+		for i := 0; i < b.N; i++ {
+			for _, v := range data {
+				heap.Push(h, v)
+			}
+			for h.Len() != 0 {
+				heap.Pop(h)
+			}
+		}
+	})
+}
+
+func BenchmarkBTree(b *testing.B) {
+	n := 100000
+	data := make([]int, n)
+	rand.Seed(0)
+	randomInts(data)
+	b.Run("Insert", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			tr := NewBTree[int]()
+			for _, v := range data {
+				tr.Insert(v)
+			}
+		}
+	})
+	b.Run("Load", func(b *testing.B) {
+		sorted := Clone(data)
+		Sort(sorted)
+		for i := 0; i < b.N; i++ {
+			NewBTree[int]().Load(sorted)
+		}
+	})
+	tr := NewBTree[int]()
+	for _, v := range data {
+		tr.Insert(v)
+	}
+	b.Run("Rank", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for _, v := range data {
+				tr.Rank(v)
+			}
+		}
+	})
+}
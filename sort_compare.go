@@ -0,0 +1,110 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build ignore
+
+// This file is not compiled. genzfunc.go uses the functions here instead of
+// the ones with same names in sort_ordered.go to generate zfunc_d.go and
+// zfunc_e.go, where cmp.Compare stands for the three-way comparison function.
+// They tell equality with one call, which takes two calls of cmp.Less.
+
+package slices
+
+import (
+	"cmp"
+)
+
+func binarySearch[E cmp.Ordered](list []E, x E) (int, bool) {
+	a, b, found := 0, len(list), false
+	for a < b {
+		m := int(uint(a+b) / 2)
+		if c := cmp.Compare(list[m], x); c < 0 {
+			a = m + 1
+		} else {
+			b, found = m, c == 0
+		}
+	}
+	return a, found
+}
+
+func gallopSearch[E cmp.Ordered](list []E, x E, hint int) (int, bool) {
+	hint = max(min(hint, len(list)), 0)
+	// Elements in list[:a] are less than x, and list[b:] are not.
+	// found tells whether list[b] equals x.
+	a, b, found := 0, len(list), false
+	c := 1
+	if hint < len(list) {
+		c = cmp.Compare(list[hint], x)
+	}
+	if c < 0 {
+		a = hint + 1
+		for step := 1; a+step <= len(list); step *= 2 {
+			m := a + step - 1
+			if c := cmp.Compare(list[m], x); c >= 0 {
+				b, found = m, c == 0
+				break
+			}
+			a = m + 1
+		}
+	} else {
+		b, found = hint, c == 0
+		for step := 1; b-step >= 0; step *= 2 {
+			m := b - step
+			c := cmp.Compare(list[m], x)
+			if c < 0 {
+				a = m + 1
+				break
+			}
+			b, found = m, c == 0
+		}
+	}
+	for a < b {
+		m := int(uint(a+b) / 2)
+		if c := cmp.Compare(list[m], x); c < 0 {
+			a = m + 1
+		} else {
+			b, found = m, c == 0
+		}
+	}
+	return b, found
+}
+
+func compareFirst[E cmp.Ordered](a, b []E) int {
+	return cmp.Compare(a[0], b[0])
+}
+
+func triPartition[E cmp.Ordered](list []E) (l, r int) {
+	size := len(list)
+	m, s := size/2, size/4
+	// Get a guide to avoid skewness.
+	x, l, _, r, y := sortIndex5(list, m-s, m-1, m, m+1, m+s)
+	if cmp.Compare(list[l], list[r]) != 0 {
+		return dualPartition(list, x, l, r, y)
+	}
+	// Dual pivot partition takes two calls to place an element. With equal
+	// pivots, which means many equal elements, one call is enough.
+	return pivotPartition(list, l)
+}
+
+// pivotPartition divides list into elements less than, equal to and greater
+// than list[p], and returns the bounds of the middle segment like triPartition.
+func pivotPartition[E cmp.Ordered](list []E, p int) (l, r int) {
+	pivot := list[p]
+	l, r = 0, len(list)
+	for k := 0; k < r; {
+		if c := cmp.Compare(list[k], pivot); c < 0 {
+			list[l], list[k] = list[k], list[l]
+			observe[E](eventSwap, 1)
+			l++
+			k++
+		} else if c > 0 {
+			r--
+			list[k], list[r] = list[r], list[k]
+			observe[E](eventSwap, 1)
+		} else {
+			k++
+		}
+	}
+	return l, r - 1
+}
@@ -15,6 +15,62 @@ func log2Ceil(num uint) int {
 	return bits.Len(num)
 }
 
+const (
+	stableMinRun    = 24
+	stableMinGallop = 7
+)
+
+// nodePower computes the power of the boundary between run [a,b) and [b,c)
+// in a list of size n, which is the depth of the node in a nearly-optimal
+// merge tree.
+func nodePower(a, b, c, n int) int {
+	x, y := a+b, b+c // doubled midpoints of the runs
+	power := 0
+	for {
+		power++
+		if x >= n {
+			x -= n
+			y -= n
+		} else if y >= n {
+			return power
+		}
+		x <<= 1
+		y <<= 1
+	}
+}
+
+// powerMerge merges natural runs in a list of size n by the policy of
+// Powersort, and the first run is [0,b). run(a) returns the end of the run
+// starting at a, and merge(a, b, c) merges the adjacent runs [a,b) and [b,c).
+// It gives up when merge returns false.
+func powerMerge(n, b int, run func(a int) int, merge func(a, b, c int) bool) bool {
+	// Powers on the stack are strictly increasing, 64 levels are enough.
+	var starts, powers [64]int
+	a, top := 0, 0
+	for b < n {
+		c := run(b)
+		p := nodePower(a, b, c, n)
+		for top > 0 && powers[top-1] > p {
+			top--
+			if !merge(starts[top], a, b) {
+				return false
+			}
+			a = starts[top]
+		}
+		starts[top], powers[top] = a, p
+		top++
+		a, b = b, c
+	}
+	for top > 0 {
+		top--
+		if !merge(starts[top], a, n) {
+			return false
+		}
+		a = starts[top]
+	}
+	return true
+}
+
 func binarySearch[E cmp.Ordered](list []E, x E) (int, bool) {
 	a, b := 0, len(list)
 	for a < b {
@@ -31,6 +87,41 @@ func binarySearch[E cmp.Ordered](list []E, x E) (int, bool) {
 	return a, true
 }
 
+// lowerBound returns the position of the first element not less than x.
+func lowerBound[E cmp.Ordered](list []E, x E) int {
+	a, b := 0, len(list)
+	for a < b {
+		m := int(uint(a+b) / 2)
+		if cmp.Less(list[m], x) {
+			a = m + 1
+		} else {
+			b = m
+		}
+	}
+	return a
+}
+
+// upperBound returns the position of the first element greater than x.
+func upperBound[E cmp.Ordered](list []E, x E) int {
+	a, b := 0, len(list)
+	for a < b {
+		m := int(uint(a+b) / 2)
+		if cmp.Less(x, list[m]) {
+			b = m
+		} else {
+			a = m + 1
+		}
+	}
+	return a
+}
+
+// equalRange returns the range of elements equal to x. Runs of equal elements
+// are usually short, so the end is found by galloping.
+func equalRange[E cmp.Ordered](list []E, x E) (int, int) {
+	a := lowerBound(list, x)
+	return a, a + gallopUpper(list[a:], x)
+}
+
 func isSorted[E cmp.Ordered](list []E) bool {
 	for i := 1; i < len(list); i++ {
 		if cmp.Less(list[i], list[i-1]) {
@@ -83,9 +174,11 @@ func sortFast[E cmp.Ordered](list []E) {
 			hint = hintSorted
 		}
 		if hint == hintSorted && isSorted(list) {
+			observe[E](eventPresorted, 0)
 			return
 		}
 
+		observe[E](eventPartition, chance)
 		l, r := 0, size-1
 		for {
 			for cmp.Less(list[l], pivot) {
@@ -98,6 +191,7 @@ func sortFast[E cmp.Ordered](list []E) {
 				break
 			}
 			list[l], list[r] = list[r], list[l]
+			observe[E](eventSwap, 1)
 			l++
 			r--
 		}
@@ -140,38 +234,178 @@ func median[E cmp.Ordered](list []E, a, b, c int) (int, uint8) {
 }
 
 // Avoid allocating O(n) size extra memory when inplace flag is set.
+// Natural runs are detected and merged by the policy of Powersort, see J. Ian
+// Munro and Sebastian Wild, "Nearly-Optimal Mergesorts: Fast, Practical
+// Sorting Methods That Optimally Adapt to Existing Runs", ESA 2018.
 func sortStable[E cmp.Ordered](list []E, inplace bool) {
-	if size := len(list); size < 16 {
-		simpleSort(list)
-	} else if inplace {
-		step := 8
-		a, b := 0, step
-		for b <= size {
-			simpleSort(list[a:b])
-			a = b
-			b += step
-		}
-		simpleSort(list[a:])
-
-		for step < size {
-			a, b = 0, step*2
-			for b <= size {
-				symmerge(list[a:b], step)
-				a = b
-				b += step * 2
-			}
-			if a+step < size {
-				symmerge(list[a:], step)
-			}
-			step *= 2
+	size := len(list)
+	b := extendRun(list)
+	if b == size {
+		observe[E](eventPresorted, 0)
+		return
+	}
+	var buf []E
+	if !inplace {
+		buf = make([]E, size/2)
+		observe[E](eventAlloc, size/2)
+	}
+	powerMerge(size, b, func(a int) int {
+		return a + extendRun(list[a:])
+	}, func(a, b, c int) bool {
+		mergeRuns(list[a:c], b-a, buf)
+		return true
+	})
+}
+
+// extendRun returns the length of the natural run at the head of list, strictly
+// descending one is reversed. Short run is extended by insertion.
+func extendRun[E cmp.Ordered](list []E) int {
+	n := min(len(list), 2)
+	if n < 2 {
+		return n
+	}
+	if cmp.Less(list[1], list[0]) {
+		for n < len(list) && cmp.Less(list[n], list[n-1]) {
+			n++
+		}
+		Reverse(list[:n])
+	} else {
+		for n < len(list) && !cmp.Less(list[n], list[n-1]) {
+			n++
+		}
+	}
+	// Linear insertion makes more comparisons than binary insertion, but the
+	// search is predictable and moves elements along, so it is faster.
+	for end := min(len(list), stableMinRun); n < end; n++ {
+		curr := list[n]
+		pos := n
+		for ; pos > 0 && cmp.Less(curr, list[pos-1]); pos-- {
+			list[pos] = list[pos-1]
 		}
+		list[pos] = curr
+	}
+	return n
+}
+
+// mergeRuns merges the sorted list[:border] and list[border:]. Elements
+// already in place are skipped by galloping first. It works in place when buf
+// is nil, otherwise buf should be able to hold the shorter run.
+func mergeRuns[E cmp.Ordered](list []E, border int, buf []E) {
+	if buf == nil {
+		mergeInPlace(list, border)
+		return
+	}
+	a := gallopUpper(list[:border], list[border])
+	if a == border {
+		return
+	}
+	b := len(list) - gallopLowerTail(list[border:], list[border-1])
+	list, border = list[a:b], border-a
+	if border <= len(list)-border {
+		mergeLow(list, border, buf)
 	} else {
-		temp := make([]E, size)
-		copy(temp, list)
-		mergeSort(temp, list)
+		mergeHigh(list, border, buf)
 	}
 }
 
+// mergeLow merges with the left run moved to buf, from the head to the tail.
+// It switches to galloping mode when one run keeps winning.
+func mergeLow[E cmp.Ordered](list []E, border int, buf []E) {
+	left := buf[:border]
+	copy(left, list[:border])
+	i, j, k := 0, border, 0
+	wl, wr := 0, 0
+	for i < len(left) && j < len(list) {
+		if cmp.Less(list[j], left[i]) {
+			list[k] = list[j]
+			j++
+			k++
+			wl = 0
+			if wr++; wr >= stableMinGallop {
+				n := gallopLower(list[j:], left[i])
+				copy(list[k:], list[j:j+n])
+				j += n
+				k += n
+				wr = 0
+			}
+		} else {
+			list[k] = left[i]
+			i++
+			k++
+			wr = 0
+			if wl++; wl >= stableMinGallop {
+				n := gallopUpper(left[i:], list[j])
+				copy(list[k:], left[i:i+n])
+				i += n
+				k += n
+				wl = 0
+			}
+		}
+	}
+	copy(list[k:], left[i:])
+}
+
+// mergeHigh merges with the right run moved to buf, from the tail to the head.
+// It switches to galloping mode when one run keeps winning.
+func mergeHigh[E cmp.Ordered](list []E, border int, buf []E) {
+	right := buf[:len(list)-border]
+	copy(right, list[border:])
+	i, j, k := border-1, len(right)-1, len(list)-1
+	wl, wr := 0, 0
+	for i >= 0 && j >= 0 {
+		if cmp.Less(right[j], list[i]) {
+			list[k] = list[i]
+			i--
+			k--
+			wr = 0
+			if wl++; wl >= stableMinGallop {
+				n := gallopUpperTail(list[:i+1], right[j])
+				copy(list[k-n+1:k+1], list[i-n+1:i+1])
+				i -= n
+				k -= n
+				wl = 0
+			}
+		} else {
+			list[k] = right[j]
+			j--
+			k--
+			wl = 0
+			if wr++; wr >= stableMinGallop {
+				n := gallopLowerTail(right[:j+1], list[i])
+				copy(list[k-n+1:k+1], right[j-n+1:j+1])
+				j -= n
+				k -= n
+				wr = 0
+			}
+		}
+	}
+	copy(list[:j+1], right[:j+1])
+}
+
+// gallopLowerTail returns the number of trailing elements in list which are
+// not less than x. It's an exponential search from the tail.
+func gallopLowerTail[E cmp.Ordered](list []E, x E) int {
+	n := len(list)
+	a, b := 0, 1
+	for b <= n && !cmp.Less(list[n-b], x) {
+		a, b = b, b*2
+	}
+	c := n - min(b-1, n)
+	return n - c - lowerBound(list[c:n-a], x)
+}
+
+// gallopUpperTail returns the number of trailing elements in list which are
+// greater than x, like gallopLowerTail.
+func gallopUpperTail[E cmp.Ordered](list []E, x E) int {
+	n := len(list)
+	a, b := 0, 1
+	for b <= n && cmp.Less(x, list[n-b]) {
+		a, b = b, b*2
+	}
+	c := n - min(b-1, n)
+	return n - c - upperBound(list[c:n-a], x)
+}
+
 func partlySort[E cmp.Ordered](list []E, k int) {
 	if len(list) < 2 || k <= 0 {
 		return
@@ -214,6 +448,7 @@ func heapSort[E cmp.Ordered](list []E) {
 		list[0], list[end] = list[end], list[0]
 		heapDown(list[:end], 0)
 	}
+	observe[E](eventSwap, len(list)-1)
 }
 
 func heapDown[E cmp.Ordered](list []E, pos int) {
@@ -230,12 +465,13 @@ func heapDown[E cmp.Ordered](list []E, pos int) {
 		pos, kid = kid, kid*2+1
 	}
 	if kid == last && cmp.Less(curr, list[kid]) {
-		list[pos], pos = list[kid], kid
+		list[pos] = list[kid]
+		pos = kid
 	}
 	list[pos] = curr
 }
 
-// Sort 5 elemnt in list with 7 comparison.
+// Sort 5 elements in list with 7 comparisons.
 func sortIndex5[E cmp.Ordered](list []E,
 	a, b, c, d, e int) (int, int, int, int, int) {
 	if cmp.Less(list[b], list[a]) {
@@ -296,19 +532,25 @@ func sortIndex5[E cmp.Ordered](list []E,
 }
 
 // triPartition divides list into 3 segments.
-// Eents before list[l] are all not greater than it.
-// Eents after list[r] are all not less than it.
+// Elements before list[l] are all not greater than it.
+// Elements after list[r] are all not less than it.
 func triPartition[E cmp.Ordered](list []E) (l, r int) {
 	size := len(list)
 	m, s := size/2, size/4
 	// Get a guide to avoid skewness.
 	x, l, _, r, y := sortIndex5(list, m-s, m-1, m, m+1, m+s)
+	return dualPartition(list, x, l, r, y)
+}
 
-	s = size - 1
+// dualPartition does the partition for triPartition with pivots list[l] and
+// list[r], and moves list[x] and list[y] to the inner ends as sentinels.
+func dualPartition[E cmp.Ordered](list []E, x, l, r, y int) (int, int) {
+	s := len(list) - 1
 	pivotL, pivotR := list[l], list[r]
 	list[l], list[r] = list[0], list[s]
 	list[1], list[x] = list[x], list[1]
 	list[s-1], list[y] = list[y], list[s-1]
+	observe[E](eventSwap, 4)
 
 	//  | less than pivotL | between pivotL and pivotR | greater than pivotR |
 	// 0|                  |l        k -- untested -- r|                     |s
@@ -323,6 +565,7 @@ func triPartition[E cmp.Ordered](list []E) (l, r int) {
 		}
 		if cmp.Less(pivotR, list[l]) {
 			list[l], list[r] = list[r], list[l]
+			observe[E](eventSwap, 1)
 			r--
 			if cmp.Less(list[l], pivotL) {
 				l++
@@ -342,13 +585,16 @@ func triPartition[E cmp.Ordered](list []E) (l, r int) {
 			}
 			if cmp.Less(list[r], pivotL) {
 				list[l], list[k], list[r] = list[r], list[l], list[k]
+				observe[E](eventSwap, 2)
 				l++
 			} else {
 				list[k], list[r] = list[r], list[k]
+				observe[E](eventSwap, 1)
 			}
 			r--
 		} else if cmp.Less(list[k], pivotL) {
 			list[k], list[l] = list[l], list[k]
+			observe[E](eventSwap, 1)
 			l++
 		}
 	}
@@ -357,11 +603,21 @@ func triPartition[E cmp.Ordered](list []E) (l, r int) {
 	r++
 	list[0], list[l] = list[l], pivotL
 	list[s], list[r] = list[r], pivotR
+	observe[E](eventSwap, 2)
 	return l, r
 }
 
+// partlySelect moves the smallest k elements to list[:k], and puts the k-th
+// one at list[k-1]. Like introSort, it falls back to heapSelect when the
+// partitions keep going skewed.
 func partlySelect[E cmp.Ordered](list []E, k int) {
+	chance := log2Ceil(uint(len(list))) * 3 / 2
 	for len(list) > 14 {
+		if chance--; chance < 0 {
+			observe[E](eventHeapSort, len(list))
+			heapSelect(list, k)
+			return
+		}
 		l, r := triPartition(list)
 		switch {
 		case k <= l:
@@ -381,19 +637,38 @@ func partlySelect[E cmp.Ordered](list []E, k int) {
 	simpleSort(list)
 }
 
+// heapSelect moves the smallest k elements to list[:k] with a max-heap,
+// and puts the greatest one of them at list[k-1].
+func heapSelect[E cmp.Ordered](list []E, k int) {
+	heap := list[:k]
+	for idx := k/2 - 1; idx >= 0; idx-- {
+		heapDown(heap, idx)
+	}
+	for i := k; i < len(list); i++ {
+		if cmp.Less(list[i], heap[0]) {
+			heap[0], list[i] = list[i], heap[0]
+			observe[E](eventSwap, 1)
+			heapDown(heap, 0)
+		}
+	}
+	heap[0], heap[k-1] = heap[k-1], heap[0]
+}
+
 func introSort[E cmp.Ordered](list []E, chance int) {
 	for len(list) > 14 {
 		if chance--; chance < 0 {
+			observe[E](eventHeapSort, len(list))
 			heapSort(list)
 			return
 		}
-		// Dual pivot quicksort need less memory access, witch makes it faster
+		observe[E](eventPartition, chance)
+		// Dual pivot quicksort need less memory access, which makes it faster
 		// than single pivot version in many cases, but not always.
 		l, r := triPartition(list)
 		introSort(list[:l], chance)
 		introSort(list[r+1:], chance)
 		if !cmp.Less(list[l], list[r]) {
-			return // All emelents in the middle segemnt are equal.
+			return // All elements in the middle segment are equal.
 		}
 		list = list[l+1 : r]
 	}
@@ -538,3 +813,642 @@ func mergeSort[E cmp.Ordered](a, b []E) {
 		}
 	}
 }
+
+// parallelSortFast is the concurrent version of sortFast.
+func parallelSortFast[E cmp.Ordered](list []E, pool *workerPool) {
+	size := len(list)
+	a, b, c := size/4, size/2, size*3/4
+	a, ha := median(list, a-1, a, a+1)
+	b, hb := median(list, b-1, b, b+1)
+	c, hc := median(list, c-1, c, c+1)
+	_, hint := median(list, a, b, c)
+	hint &= ha & hb & hc
+
+	if hint == hintRevered {
+		reverse(list)
+		hint = hintSorted
+	}
+	if hint == hintSorted && isSorted(list) {
+		return
+	}
+	parallelSort(list, log2Ceil(uint(size))*3/2, pool)
+}
+
+// parallelSort hands segments produced by triPartition over to the pool,
+// small segments are left to introSort.
+func parallelSort[E cmp.Ordered](list []E, chance int, pool *workerPool) {
+	if len(list) <= parallelSize {
+		introSort(list, chance)
+		return
+	}
+	if chance--; chance < 0 {
+		observe[E](eventHeapSort, len(list))
+		heapSort(list)
+		return
+	}
+	l, r := triPartition(list)
+	pool.fork(func() {
+		parallelSort(list[:l], chance, pool)
+	}, func() {
+		parallelSort(list[r+1:], chance, pool)
+	}, func() {
+		if cmp.Less(list[l], list[r]) {
+			parallelSort(list[l+1:r], chance, pool)
+		}
+	})
+}
+
+// parallelMergeSort is the concurrent version of mergeSort.
+func parallelMergeSort[E cmp.Ordered](a, b []E, pool *workerPool) {
+	size := len(a)
+	if size <= parallelSize {
+		mergeSort(a, b)
+		return
+	}
+	half := size / 2
+	pool.fork(func() {
+		parallelMergeSort(b[:half], a[:half], pool)
+	}, func() {
+		parallelMergeSort(b[half:], a[half:], pool)
+	})
+	parallelMerge(a[:half], a[half:], b, pool)
+}
+
+// parallelMerge merges sorted x and y into out. Elements from x go first
+// when they are equal to ones from y.
+func parallelMerge[E cmp.Ordered](x, y, out []E, pool *workerPool) {
+	if len(x)+len(y) <= parallelSize {
+		i, j, k := 0, 0, 0
+		for ; i < len(x) && j < len(y); k++ {
+			if cmp.Less(y[j], x[i]) {
+				out[k] = y[j]
+				j++
+			} else {
+				out[k] = x[i]
+				i++
+			}
+		}
+		k += copy(out[k:], x[i:])
+		copy(out[k:], y[j:])
+		return
+	}
+
+	// Split both sides at a pivot from the longer one, so that all elements
+	// of the left parts can be placed before those of the right parts.
+	var i, j int
+	if len(x) >= len(y) {
+		i = len(x) / 2
+		pivot := x[i]
+		a, b := 0, len(y)
+		for a < b {
+			m := int(uint(a+b) / 2)
+			if cmp.Less(y[m], pivot) {
+				a = m + 1
+			} else {
+				b = m
+			}
+		}
+		j = a
+	} else {
+		j = len(y) / 2
+		pivot := y[j]
+		a, b := 0, len(x)
+		for a < b {
+			m := int(uint(a+b) / 2)
+			if cmp.Less(pivot, x[m]) {
+				b = m
+			} else {
+				a = m + 1
+			}
+		}
+		i = a
+	}
+	pool.fork(func() {
+		parallelMerge(x[:i], y[:j], out[:i+j], pool)
+	}, func() {
+		parallelMerge(x[i:], y[j:], out[i+j:], pool)
+	})
+}
+
+// mergeTo merges sorted lists into out, which should be as long as the sum
+// of them. Elements from former lists go first when they are equal.
+func mergeTo[E cmp.Ordered](out []E, lists [][]E) {
+	lists = append([][]E(nil), lists...)
+	heap := make([]int, 0, len(lists))
+	for i := 0; i < len(lists); i++ {
+		if len(lists[i]) != 0 {
+			heap = append(heap, i)
+		}
+	}
+	for i := len(heap)/2 - 1; i >= 0; i-- {
+		mergeHeapDown(lists, heap, i)
+	}
+
+	k := 0
+	for len(heap) > 2 {
+		top := heap[0]
+		out[k] = lists[top][0]
+		k++
+		if lists[top] = lists[top][1:]; len(lists[top]) == 0 {
+			last := len(heap) - 1
+			heap[0] = heap[last]
+			heap = heap[:last]
+		}
+		mergeHeapDown(lists, heap, 0)
+	}
+
+	switch len(heap) {
+	case 1:
+		copy(out[k:], lists[heap[0]])
+	case 2:
+		a, b := lists[heap[0]], lists[heap[1]]
+		if heap[1] < heap[0] {
+			a, b = b, a
+		}
+		i, j := 0, 0
+		for ; i < len(a) && j < len(b); k++ {
+			if cmp.Less(b[j], a[i]) {
+				out[k] = b[j]
+				j++
+			} else {
+				out[k] = a[i]
+				i++
+			}
+		}
+		k += copy(out[k:], a[i:])
+		copy(out[k:], b[j:])
+	}
+}
+
+// mergeBefore reports whether the head of lists[x] should go before the head
+// of lists[y].
+func mergeBefore[E cmp.Ordered](lists [][]E, x, y int) bool {
+	if x < y {
+		return !cmp.Less(lists[y][0], lists[x][0])
+	}
+	return cmp.Less(lists[x][0], lists[y][0])
+}
+
+func mergeHeapDown[E cmp.Ordered](lists [][]E, heap []int, pos int) {
+	curr := heap[pos]
+	last := len(heap) - 1
+	for kid := pos*2 + 1; kid <= last; kid = pos*2 + 1 {
+		if kid < last && mergeBefore(lists, heap[kid+1], heap[kid]) {
+			kid++
+		}
+		if !mergeBefore(lists, heap[kid], curr) {
+			break
+		}
+		heap[pos] = heap[kid]
+		pos = kid
+	}
+	heap[pos] = curr
+}
+
+// mergeInPlace merges list[:border] and list[border:] stably without extra
+// memory. Elements already in place are skipped by galloping first.
+func mergeInPlace[E cmp.Ordered](list []E, border int) {
+	if border <= 0 || border >= len(list) {
+		return
+	}
+	a := gallopUpper(list[:border], list[border])
+	if a == border {
+		return
+	}
+	b := len(list) - gallopLowerTail(list[border:], list[border-1])
+	symmerge(list[a:b], border-a)
+}
+
+// multiSelect moves elements to positions in ks as if list is sorted.
+// Elements between those positions are partitioned accordingly.
+// ks should be ascending, and it will be modified.
+func multiSelect[E cmp.Ordered](list []E, ks []int) {
+	introSelect(list, ks, log2Ceil(uint(len(list)))*3/2)
+}
+
+// introSelect is the recursive part of multiSelect. Like introSort, it falls
+// back to heapSort when chance runs out.
+func introSelect[E cmp.Ordered](list []E, ks []int, chance int) {
+	for len(ks) != 0 {
+		if len(list) <= 14 {
+			simpleSort(list)
+			return
+		}
+		if chance--; chance < 0 {
+			observe[E](eventHeapSort, len(list))
+			heapSort(list)
+			return
+		}
+		l, r := triPartition(list)
+		a := 0
+		for a < len(ks) && ks[a] < l {
+			a++
+		}
+		b := a
+		for b < len(ks) && ks[b] <= r {
+			b++
+		}
+		introSelect(list[:l], ks[:a], chance)
+
+		mid := ks[a:b]
+		if len(mid) != 0 && mid[0] == l {
+			mid = mid[1:]
+		}
+		if len(mid) != 0 && mid[len(mid)-1] == r {
+			mid = mid[:len(mid)-1]
+		}
+		// All elements in the middle segment are equal when pivots are equal.
+		if len(mid) != 0 && cmp.Less(list[l], list[r]) {
+			for i := 0; i < len(mid); i++ {
+				mid[i] -= l + 1
+			}
+			introSelect(list[l+1:r], mid, chance)
+		}
+
+		ks = ks[b:]
+		for i := 0; i < len(ks); i++ {
+			ks[i] -= r + 1
+		}
+		list = list[r+1:]
+	}
+}
+
+// gallopLower returns the number of leading elements in list which are less
+// than x. It's an exponential search from the head, so it's fast when the
+// result is small.
+func gallopLower[E cmp.Ordered](list []E, x E) int {
+	a, b := 0, 1
+	for b <= len(list) && cmp.Less(list[b-1], x) {
+		a, b = b, b*2
+	}
+	pos, _ := binarySearch(list[a:min(b-1, len(list))], x)
+	return a + pos
+}
+
+// gallopUpper returns the number of leading elements in list which are not
+// greater than x, like gallopLower.
+func gallopUpper[E cmp.Ordered](list []E, x E) int {
+	a, b := 0, 1
+	for b <= len(list) && !cmp.Less(x, list[b-1]) {
+		a, b = b, b*2
+	}
+	for b = min(b-1, len(list)); a < b; {
+		m := int(uint(a+b) / 2)
+		if cmp.Less(x, list[m]) {
+			b = m
+		} else {
+			a = m + 1
+		}
+	}
+	return a
+}
+
+// appendUnique appends elements of sorted list to dst, equal ones only once.
+func appendUnique[E cmp.Ordered](dst, list []E) []E {
+	for len(list) != 0 {
+		dst = append(dst, list[0])
+		list = list[gallopUpper(list[1:], list[0])+1:]
+	}
+	return dst
+}
+
+// compareFirst compares a[0] and b[0] like cmp.Compare.
+func compareFirst[E cmp.Ordered](a, b []E) int {
+	if cmp.Less(a[0], b[0]) {
+		return -1
+	}
+	if cmp.Less(b[0], a[0]) {
+		return 1
+	}
+	return 0
+}
+
+// setOperate appends the result of set operation op on sorted a and b to dst.
+// Equal elements are counted as multiset when multi is set, otherwise they
+// are regarded as one. Elements in a are preferred when output equal ones.
+func setOperate[E cmp.Ordered](dst, a, b []E, op setOp, multi bool) []E {
+	keepA := op != setIntersect
+	keepB := op == setUnion || op == setSymmetricDifference
+	for len(a) != 0 && len(b) != 0 {
+		if c := compareFirst(a, b); c < 0 {
+			n := gallopLower(a[1:], b[0]) + 1
+			if keepA && multi {
+				dst = append(dst, a[:n]...)
+			} else if keepA {
+				dst = appendUnique(dst, a[:n])
+			}
+			a = a[n:]
+		} else if c > 0 {
+			n := gallopLower(b[1:], a[0]) + 1
+			if keepB && multi {
+				dst = append(dst, b[:n]...)
+			} else if keepB {
+				dst = appendUnique(dst, b[:n])
+			}
+			b = b[n:]
+		} else {
+			na := gallopUpper(a[1:], b[0]) + 1
+			nb := gallopUpper(b[1:], a[0]) + 1
+			if !multi {
+				if op == setUnion || op == setIntersect {
+					dst = append(dst, a[0])
+				}
+			} else if op == setUnion {
+				dst = append(dst, a[:na]...)
+				if nb > na {
+					dst = append(dst, b[na:nb]...)
+				}
+			} else if op == setIntersect {
+				dst = append(dst, a[:min(na, nb)]...)
+			} else if na > nb {
+				dst = append(dst, a[nb:na]...)
+			} else if op == setSymmetricDifference && nb > na {
+				dst = append(dst, b[na:nb]...)
+			}
+			a, b = a[na:], b[nb:]
+		}
+	}
+	if keepA && multi {
+		dst = append(dst, a...)
+	} else if keepA {
+		dst = appendUnique(dst, a)
+	}
+	if keepB && multi {
+		dst = append(dst, b...)
+	} else if keepB {
+		dst = appendUnique(dst, b)
+	}
+	return dst
+}
+
+// isSubset reports whether every element of sorted a is in sorted b.
+// Equal elements are counted as multiset when multi is set.
+func isSubset[E cmp.Ordered](a, b []E, multi bool) bool {
+	for len(a) != 0 {
+		if multi && len(a) > len(b) {
+			return false
+		}
+		n, found := gallopSearch(b, a[0], 0)
+		if !found {
+			return false
+		}
+		b = b[n:]
+		na := gallopUpper(a[1:], a[0]) + 1
+		if multi {
+			nb := gallopUpper(b[1:], a[0]) + 1
+			if nb < na {
+				return false
+			}
+			b = b[nb:]
+		}
+		a = a[na:]
+	}
+	return true
+}
+
+// compactSorted replaces every run of equal elements in sorted list with the
+// first one. Lengths of the runs are appended to counts when count is set.
+func compactSorted[E cmp.Ordered](list []E, counts []int, count bool) ([]E, []int) {
+	m := 0
+	for i := 0; i < len(list); m++ {
+		n := gallopUpper(list[i+1:], list[i]) + 1
+		list[m] = list[i]
+		if count {
+			counts = append(counts, n)
+		}
+		i += n
+	}
+	return list[:m], counts
+}
+
+// gallopSearch works like binarySearch, but searches around hint by
+// exponential search. It's fast when the result is close to hint.
+func gallopSearch[E cmp.Ordered](list []E, x E, hint int) (int, bool) {
+	hint = max(min(hint, len(list)), 0)
+	a := hint
+	if hint < len(list) && cmp.Less(list[hint], x) {
+		a += gallopLower(list[hint+1:], x) + 1
+	} else {
+		b, step := hint, 1
+		for a = b - step; a >= 0 && !cmp.Less(list[a], x); a = b - step {
+			b = a
+			step *= 2
+		}
+		a = max(a+1, 0)
+		a += lowerBound(list[a:b], x)
+	}
+	return a, a < len(list) && !cmp.Less(x, list[a])
+}
+
+// sortFastCancel works like sortFast, but checks done at partition boundaries
+// of long segments. It returns false when cancelled, list is left as a
+// permutation of the input.
+func sortFastCancel[E cmp.Ordered](list []E, done cancelSignal) bool {
+	chance := log2Ceil(uint(len(list))) * 3 / 2
+	return introSortCancel(list, chance, done)
+}
+
+func introSortCancel[E cmp.Ordered](list []E, chance int, done cancelSignal) bool {
+	for len(list) > cancelChunk {
+		if isDone(done) {
+			return false
+		}
+		if chance--; chance < 0 {
+			observe[E](eventHeapSort, len(list))
+			heapSort(list)
+			return true
+		}
+		l, r := triPartition(list)
+		if !introSortCancel(list[:l], chance, done) ||
+			!introSortCancel(list[r+1:], chance, done) {
+			return false
+		}
+		if !cmp.Less(list[l], list[r]) {
+			return true // All elements in the middle segment are equal.
+		}
+		list = list[l+1 : r]
+	}
+	if isDone(done) {
+		return false
+	}
+	sortFast(list)
+	return true
+}
+
+// sortStableCancel works like sortStable, but checks done while merging runs
+// across chunk boundaries. It returns false when cancelled, list is left as a
+// permutation of the input.
+func sortStableCancel[E cmp.Ordered](list []E, done cancelSignal) bool {
+	size := len(list)
+	b := extendRun(list)
+	if b == size {
+		return true
+	}
+	buf := make([]E, size/2)
+	observe[E](eventAlloc, size/2)
+	return powerMerge(size, b, func(a int) int {
+		return a + extendRun(list[a:])
+	}, func(a, b, c int) bool {
+		if c-a <= cancelChunk {
+			if a/cancelChunk != (c-1)/cancelChunk && isDone(done) {
+				return false
+			}
+			mergeRuns(list[a:c], b-a, buf)
+			return true
+		}
+		return mergeCancel(list[a:c], b-a, buf, done)
+	})
+}
+
+// mergeCancel works like mergeRuns with buf, but checks done every cancelChunk
+// elements. When cancelled, the rest of the run in buf is put back into the
+// vacant slots, so list is left as a permutation.
+func mergeCancel[E cmp.Ordered](list []E, border int, buf []E, done cancelSignal) bool {
+	a := gallopUpper(list[:border], list[border])
+	if a == border {
+		return true
+	}
+	b := len(list) - gallopLowerTail(list[border:], list[border-1])
+	list, border = list[a:b], border-a
+
+	if border <= len(list)-border {
+		// Merge from the head with the left run moved to buf.
+		left := buf[:border]
+		copy(left, list[:border])
+		i, j, k := 0, border, 0
+		for i < len(left) && j < len(list) {
+			if isDone(done) {
+				break
+			}
+			for end := k + cancelChunk; k < end && i < len(left) && j < len(list); k++ {
+				if cmp.Less(list[j], left[i]) {
+					list[k] = list[j]
+					j++
+				} else {
+					list[k] = left[i]
+					i++
+				}
+			}
+		}
+		copy(list[k:], left[i:])
+		return i == len(left) || j == len(list)
+	}
+
+	// Merge from the tail with the right run moved to buf.
+	right := buf[:len(list)-border]
+	copy(right, list[border:])
+	i, j, k := border-1, len(right)-1, len(list)-1
+	for i >= 0 && j >= 0 {
+		if isDone(done) {
+			break
+		}
+		for end := k - cancelChunk; k > end && i >= 0 && j >= 0; k-- {
+			if cmp.Less(right[j], list[i]) {
+				list[k] = list[i]
+				i--
+			} else {
+				list[k] = right[j]
+				j--
+			}
+		}
+	}
+	copy(list[i+1:], right[:j+1])
+	return i < 0 || j < 0
+}
+
+// pushBounded pushes x into heap, which keeps the smallest k elements. It's a
+// max-heap once it's full. The new heap is returned.
+func pushBounded[E cmp.Ordered](heap []E, k int, x E) []E {
+	if len(heap) < k {
+		heap = append(heap, x)
+		if len(heap) == k {
+			for idx := k/2 - 1; idx >= 0; idx-- {
+				heapDown(heap, idx)
+			}
+		}
+	} else if k > 0 && cmp.Less(x, heap[0]) {
+		heap[0] = x
+		heapDown(heap, 0)
+	}
+	return heap
+}
+
+// pushGreatest works like pushBounded, but keeps the greatest k elements in
+// a min-heap.
+func pushGreatest[E cmp.Ordered](heap []E, k int, x E) []E {
+	if len(heap) < k {
+		heap = append(heap, x)
+		if len(heap) == k {
+			for idx := k/2 - 1; idx >= 0; idx-- {
+				heapSiftDown(heap, idx, 1, nil, nil)
+			}
+		}
+	} else if k > 0 && cmp.Less(heap[0], x) {
+		heap[0] = x
+		heapSiftDown(heap, 0, 1, nil, nil)
+	}
+	return heap
+}
+
+// heapSiftDown moves list[pos] down in a min-heap whose nodes have 1<<shift
+// children. When ids is not nil, it moves along with list, and index records
+// the position of each id. The final position is returned.
+func heapSiftDown[E cmp.Ordered](list []E, pos int, shift uint, ids, index []int) int {
+	curr, id := list[pos], 0
+	if ids != nil {
+		id = ids[pos]
+	}
+	for {
+		kid := pos<<shift + 1
+		if kid >= len(list) {
+			break
+		}
+		end := min(kid+1<<shift, len(list))
+		for k := kid + 1; k < end; k++ {
+			if cmp.Less(list[k], list[kid]) {
+				kid = k
+			}
+		}
+		if !cmp.Less(list[kid], curr) {
+			break
+		}
+		list[pos] = list[kid]
+		if ids != nil {
+			ids[pos] = ids[kid]
+			index[ids[pos]] = pos
+		}
+		pos = kid
+	}
+	list[pos] = curr
+	if ids != nil {
+		ids[pos] = id
+		index[id] = pos
+	}
+	return pos
+}
+
+// heapSiftUp moves list[pos] up in a min-heap, like heapSiftDown.
+func heapSiftUp[E cmp.Ordered](list []E, pos int, shift uint, ids, index []int) int {
+	curr, id := list[pos], 0
+	if ids != nil {
+		id = ids[pos]
+	}
+	for pos > 0 {
+		parent := (pos - 1) >> shift
+		if !cmp.Less(curr, list[parent]) {
+			break
+		}
+		list[pos] = list[parent]
+		if ids != nil {
+			ids[pos] = ids[parent]
+			index[ids[pos]] = pos
+		}
+		pos = parent
+	}
+	list[pos] = curr
+	if ids != nil {
+		ids[pos] = id
+		index[id] = pos
+	}
+	return pos
+}
@@ -6,8 +6,10 @@ package slices
 
 import (
 	"cmp"
+	"context"
 	"fmt"
 	"math"
+	"math/bits"
 	"math/rand"
 	"sort"
 	"strconv"
@@ -117,6 +119,107 @@ func TestPartlySort(t *testing.T) {
 	}
 }
 
+// adversary builds an Order against quicksort-like algorithms, from M. D.
+// McIlroy, "A Killer Adversary for Quicksort". Elements should be distinct
+// indexes in [0, n). Values are decided lazily to make partitions skewed.
+type adversary struct {
+	val       []int
+	gas       int
+	solid     int
+	candidate int
+	count     int
+}
+
+func newAdversary(n int) (*adversary, []int) {
+	adv := &adversary{val: make([]int, n), gas: n, candidate: -1}
+	data := make([]int, n)
+	for i := 0; i < n; i++ {
+		adv.val[i] = n
+		data[i] = i
+	}
+	return adv, data
+}
+
+func (adv *adversary) less(x, y int) bool {
+	adv.count++
+	if adv.val[x] == adv.gas && adv.val[y] == adv.gas {
+		if x == adv.candidate {
+			adv.val[x] = adv.solid
+		} else {
+			adv.val[y] = adv.solid
+		}
+		adv.solid++
+	}
+	if adv.val[x] == adv.gas {
+		adv.candidate = x
+	} else if adv.val[y] == adv.gas {
+		adv.candidate = y
+	}
+	return adv.val[x] < adv.val[y]
+}
+
+func TestPartlySortAdversary(t *testing.T) {
+	n := 10000
+	limit := n * log2Ceil(uint(n)) * 4
+	cases := []struct {
+		name string
+		run  func(od *Order[int], data []int)
+	}{
+		{name: "PartlySort", run: func(od *Order[int], data []int) {
+			od.PartlySort(data, 10)
+		}},
+		{name: "NthElement", run: func(od *Order[int], data []int) {
+			od.NthElement(data, n/2)
+		}},
+		{name: "Quantiles", run: func(od *Order[int], data []int) {
+			od.Quantiles(data, 0.5, 0.9, 0.99)
+		}},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			adv, data := newAdversary(n)
+			tt.run(&Order[int]{Less: adv.less}, data)
+			if adv.count > limit {
+				t.Errorf("%d comparisons on %d elements", adv.count, n)
+			}
+
+			adv, data = newAdversary(n)
+			tt.run(&Order[int]{
+				RefLess: func(x, y *int) bool {
+					return adv.less(*x, *y)
+				},
+			}, data)
+			if adv.count > limit {
+				t.Errorf("%d comparisons on %d elements by RefLess", adv.count, n)
+			}
+		})
+	}
+}
+
+func TestPartlySortOrganPipe(t *testing.T) {
+	n := 10000
+	patterns := map[string]func(i int) int{
+		"organ-pipe": func(i int) int { return min(i, n-i) },
+		"sawtooth":   func(i int) int { return i % 100 },
+		"push-front": func(i int) int { return (i + 1) % n },
+		"ascent":     func(i int) int { return i },
+		"descent":    func(i int) int { return n - i },
+	}
+	for name, gen := range patterns {
+		t.Run(name, func(t *testing.T) {
+			data := make([]int, n)
+			for i := range data {
+				data[i] = gen(i)
+			}
+			testPartlySortedInts(t, Clone(data), n/2)
+			want := Clone(data)
+			Sort(want)
+			NthElement(data, n/3)
+			checkNthElement(t, data, want, n/3)
+		})
+	}
+}
+
 func TestSortLarge_Random(t *testing.T) {
 	n := 1000000
 	if testing.Short() {
@@ -195,6 +298,93 @@ func TestStability(t *testing.T) {
 	if testing.Short() {
 		n, m = 1000, 100
 	}
+	testStability(t, n, m, intPairOrder.SortStable)
+}
+
+func TestStabilityRuns(t *testing.T) {
+	n := 10000
+	patterns := map[string]func(i int) int{
+		"appended": func(i int) int {
+			if i < n*9/10 {
+				return i / 3
+			}
+			return rand.Intn(n / 3)
+		},
+		"descending": func(i int) int { return (n - i) / 3 },
+		"sawtooth":   func(i int) int { return i % 1000 },
+		"organpipe": func(i int) int {
+			if i < n/2 {
+				return i
+			}
+			return n - i
+		},
+		"stairs": func(i int) int {
+			if i/100%2 == 0 {
+				return i / 7
+			}
+			return -i / 7
+		},
+		"plateaus": func(i int) int { return i / 1000 * (1 - i/1000%2*2) },
+	}
+	for name, gen := range patterns {
+		for _, inplace := range []bool{false, true} {
+			data := make(intPairs, n)
+			for i := 0; i < len(data); i++ {
+				data[i].a = gen(i)
+			}
+			data.initB()
+			intPairOrder.SortWithOption(data, true, inplace)
+			if !intPairOrder.IsSorted(data) {
+				t.Errorf("%s (inplace=%v): didn't sort", name, inplace)
+			}
+			if !data.inOrder() {
+				t.Errorf("%s (inplace=%v): wasn't stable", name, inplace)
+			}
+		}
+	}
+}
+
+func TestSortStableAdaptive(t *testing.T) {
+	n, tail := 100000, 100
+	ncmp := 0
+	od := Order[int]{
+		Less: func(a, b int) bool {
+			ncmp++
+			return a < b
+		}}
+	for _, inplace := range []bool{false, true} {
+		data := make([]int, n)
+		for i := 0; i < n; i++ {
+			data[i] = i
+		}
+		ncmp = 0
+		od.SortWithOption(data, true, inplace)
+		if ncmp != n-1 {
+			t.Errorf("sorted (inplace=%v): %d comparisons", inplace, ncmp)
+		}
+
+		for i := 0; i < n; i++ {
+			data[i] = n - i
+		}
+		ncmp = 0
+		od.SortWithOption(data, true, inplace)
+		if ncmp != n-1 || !IsSorted(data) {
+			t.Errorf("reversed (inplace=%v): %d comparisons", inplace, ncmp)
+		}
+
+		for i := n - tail; i < n; i++ {
+			data[i] = rand.Intn(n)
+		}
+		ncmp = 0
+		od.SortWithOption(data, true, inplace)
+		if limit := n * 2; ncmp > limit || !IsSorted(data) {
+			t.Errorf("appended (inplace=%v): %d comparisons, limit %d", inplace, ncmp, limit)
+		}
+	}
+}
+
+func testStability(t *testing.T, n, m int, sortStable func([]intPair)) {
+	t.Helper()
 	data := make(intPairs, n)
 
 	// random distribution
@@ -205,7 +395,7 @@ func TestStability(t *testing.T) {
 		t.Fatalf("terrible rand.rand")
 	}
 	data.initB()
-	intPairOrder.SortStable(data)
+	sortStable(data)
 	if !intPairOrder.IsSorted(data) {
 		t.Errorf("Stable didn't sort %d ints", n)
 	}
@@ -215,7 +405,7 @@ func TestStability(t *testing.T) {
 
 	// already sorted
 	data.initB()
-	intPairOrder.SortStable(data)
+	sortStable(data)
 	if !intPairOrder.IsSorted(data) {
 		t.Errorf("Stable shuffled sorted %d ints (order)", n)
 	}
@@ -228,7 +418,7 @@ func TestStability(t *testing.T) {
 		data[i].a = len(data) - i
 	}
 	data.initB()
-	intPairOrder.SortStable(data)
+	sortStable(data)
 	if !intPairOrder.IsSorted(data) {
 		t.Errorf("Stable didn't sort %d ints", n)
 	}
@@ -458,18 +648,16 @@ func countOps(t *testing.T, stable, inplace bool) {
 	if !testing.Verbose() {
 		t.Skip("Counting skipped as non-verbose mode.")
 	}
-	ncmp := 0
 	od := Order[int]{
 		Less: func(a, b int) bool {
-			ncmp++
 			return a < b
 		}}
+	var stats Stats
 	for _, n := range sizes {
 		data := make([]int, n)
 		for i := 0; i < n; i++ {
 			data[i] = rand.Intn(n)
 		}
-		ncmp = 0
 		name := "Sort"
 		if stable {
 			name = "StableSort"
@@ -477,8 +665,9 @@ func countOps(t *testing.T, stable, inplace bool) {
 		if inplace {
 			name += "(inplace)"
 		}
-		od.SortWithOption(data, stable, inplace)
-		t.Logf("%s %8d elements: %10d Less", name, n, ncmp)
+		od.SortWithStats(data, stable, inplace, &stats)
+		t.Logf("%s %8d elements: %10d Less, %9d Swap, depth %2d",
+			name, n, stats.Comparisons, stats.Swaps, stats.MaxDepth)
 	}
 }
 
@@ -571,3 +760,187 @@ func TestOrderPartlySort(t *testing.T) {
 		}
 	}
 }
+
+var intCompareOrder = Order[int]{
+	Compare: cmp.Compare[int],
+}
+
+func TestSortCompare(t *testing.T) {
+	data := Clone(ints[:])
+	intCompareOrder.Sort(data)
+	if !intCompareOrder.IsSorted(data) || !IsSorted(data) {
+		t.Errorf("sorted %v", ints)
+		t.Errorf("   got %v", data)
+	}
+	for i, v := range data {
+		pos, found := intCompareOrder.BinarySearch(data, v)
+		if !found || data[pos] != v || pos > i {
+			t.Errorf("BinarySearch(%d) got (%d, %v)", v, pos, found)
+		}
+	}
+	if v := intCompareOrder.Min(data); v != Min(ints[:]) {
+		t.Errorf("Min got %v", v)
+	}
+	if v := intCompareOrder.Max(data); v != Max(ints[:]) {
+		t.Errorf("Max got %v", v)
+	}
+
+	data = Clone(ints[:])
+	intCompareOrder.PartlySort(data, 5)
+	want := Clone(ints[:])
+	Sort(want)
+	if !Equal(data[:5], want[:5]) {
+		t.Errorf("PartlySort got %v, want %v", data[:5], want[:5])
+	}
+}
+
+func TestCompareCalls(t *testing.T) {
+	calls := 0
+	od := Order[int]{
+		Compare: func(a, b int) int {
+			calls++
+			return cmp.Compare(a, b)
+		},
+	}
+	data := make([]int, 1000)
+	for i := range data {
+		data[i] = i * 2
+	}
+	limit := bits.Len(uint(len(data)))
+	for x := -1; x <= len(data)*2; x++ {
+		calls = 0
+		pos, found := od.BinarySearch(data, x)
+		if pos != (x+1)/2 || found != (x%2 == 0 && x < len(data)*2) {
+			t.Fatalf("BinarySearch(%d) got (%d, %v)", x, pos, found)
+		}
+		if calls > limit {
+			t.Fatalf("BinarySearch(%d) took %d calls, want at most %d", x, calls, limit)
+		}
+	}
+
+	// With few distinct values, there are many partitions with equal pivots,
+	// which take one three-way comparison per element.
+	for i := range data {
+		data[i] = i * 7919 % 1009 % 3
+	}
+	lessCalls := 0
+	lessOrder := Order[int]{
+		Less: func(a, b int) bool {
+			lessCalls++
+			return a < b
+		},
+	}
+	lessOrder.Sort(Clone(data))
+	calls = 0
+	od.Sort(data)
+	if !IsSorted(data) {
+		t.Errorf("list with few distinct values didn't sort")
+	}
+	if calls >= lessCalls {
+		t.Errorf("Sort took %d calls, more than %d calls with Less", calls, lessCalls)
+	}
+}
+
+func testSortObjectCompare(t *testing.T, stable, inplace bool) {
+	n := 10000
+	if testing.Short() {
+		n = 1000
+	}
+	data1 := make([]smallObject, n)
+	data2 := make([]bigObject, n)
+	for i := 0; i < n; i++ {
+		val := rand.Intn(n)
+		data1[i].val = val
+		data2[i].val = val
+	}
+	od1 := Order[smallObject]{
+		Compare: func(a, b smallObject) int {
+			return cmp.Compare(a.val, b.val)
+		}}
+	od1.SortWithOption(data1, stable, inplace)
+	if !od1.IsSorted(data1) {
+		t.Errorf("small objects didn't sort")
+	}
+
+	od2 := Order[bigObject]{
+		RefCompare: func(a, b *bigObject) int {
+			return cmp.Compare(a.val, b.val)
+		}}
+	od2.SortWithOption(data2, stable, inplace)
+	if !od2.IsSorted(data2) {
+		t.Errorf("big objects didn't sort")
+	}
+}
+
+func TestSortObjectCompare(t *testing.T)              { testSortObjectCompare(t, false, false) }
+func TestSortObjectCompareInplace(t *testing.T)       { testSortObjectCompare(t, false, true) }
+func TestSortObjectCompareStable(t *testing.T)        { testSortObjectCompare(t, true, false) }
+func TestSortObjectCompareStableInplace(t *testing.T) { testSortObjectCompare(t, true, true) }
+
+func TestStabilityCompare(t *testing.T) {
+	n, m := 100000, 1000
+	if testing.Short() {
+		n, m = 1000, 100
+	}
+	od := Order[intPair]{
+		RefCompare: func(x, y *intPair) int {
+			return cmp.Compare(x.a, y.a)
+		},
+	}
+	testStability(t, n, m, od.SortStable)
+	testStability(t, n, m, func(list []intPair) {
+		od.SortWithOption(list, true, true)
+	})
+}
+
+func TestUninitializedOrder(t *testing.T) {
+	var od Order[int]
+	if !panics(func() { od.Sort([]int{2, 1}) }) {
+		t.Errorf("Sort with uninitialized Order: got no panic, want panic")
+	}
+}
+
+func TestSortSpecialFloats(t *testing.T) {
+	// Long enough to be sorted by block partition in parallel.
+	n := 100000
+	special := []float64{math.NaN(), math.Inf(1), math.Inf(-1),
+		math.Copysign(0, -1), 0}
+	data := make([]float64, n)
+	for i := range data {
+		if rand.Intn(4) == 0 {
+			data[i] = special[rand.Intn(len(special))]
+		} else {
+			data[i] = rand.NormFloat64()
+		}
+	}
+	bitsOf := func(list []float64) []uint64 {
+		out := make([]uint64, len(list))
+		for i, v := range list {
+			out[i] = math.Float64bits(v)
+		}
+		Sort(out)
+		return out
+	}
+	want := bitsOf(data)
+	for _, sorter := range []struct {
+		name string
+		fn   func([]float64)
+	}{
+		{"Sort", Sort[float64]},
+		{"ParallelSort", func(list []float64) { ParallelSort(list, 4) }},
+		{"SortContext", func(list []float64) {
+			SortContext(context.Background(), list)
+		}},
+	} {
+		for _, size := range []int{1000, 5000, n} {
+			list := Clone(data[:size])
+			sorter.fn(list)
+			if !IsSorted(list) {
+				t.Errorf("%s didn't sort %d floats with NaNs and zeros", sorter.name, size)
+			}
+			if size == n && !Equal(bitsOf(list), want) {
+				t.Errorf("%s lost special values", sorter.name)
+			}
+		}
+	}
+}
@@ -0,0 +1,182 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slices
+
+import (
+	"cmp"
+)
+
+// SortedSlice keeps elements in ascending order. Equal elements are kept in
+// the order they are added.
+// The order is checked after every modification in builds with the
+// slicesdebug tag.
+type SortedSlice[E any] struct {
+	list       []E
+	search     func(list []E, x E) (int, bool)
+	lowerBound func(list []E, x E) int
+	upperBound func(list []E, x E) int
+	sortStable func(list []E)
+	merge      func(list []E, border int)
+	isSorted   func(list []E) bool
+}
+
+// NewSortedSlice creates a SortedSlice with elements in list, which will be
+// sorted if it's not. list is used as the storage, so it should not be used
+// by the caller after that.
+func NewSortedSlice[E cmp.Ordered](list []E) *SortedSlice[E] {
+	s := &SortedSlice[E]{
+		list:       list,
+		search:     binarySearch[E],
+		lowerBound: lowerBound[E],
+		upperBound: upperBound[E],
+		sortStable: SortStable[E],
+		merge:      mergeInPlace[E],
+		isSorted:   isSorted[E],
+	}
+	s.init()
+	return s
+}
+
+// The general version of NewSortedSlice.
+func (od *Order[E]) NewSortedSlice(list []E) *SortedSlice[E] {
+	s := &SortedSlice[E]{
+		list:       list,
+		sortStable: od.SortStable,
+	}
+	switch od.kind() {
+	case kindLess:
+		algo := lessFunc[E](od.Less)
+		s.search, s.lowerBound, s.upperBound, s.merge, s.isSorted =
+			algo.binarySearch, algo.lowerBound, algo.upperBound, algo.mergeInPlace, algo.isSorted
+	case kindRefLess:
+		algo := refLessFunc[E](od.RefLess)
+		s.search, s.lowerBound, s.upperBound, s.merge, s.isSorted =
+			algo.binarySearch, algo.lowerBound, algo.upperBound, algo.mergeInPlace, algo.isSorted
+	case kindCompare:
+		algo := compareFunc[E](od.Compare)
+		s.search, s.lowerBound, s.upperBound, s.merge, s.isSorted =
+			algo.binarySearch, algo.lowerBound, algo.upperBound, algo.mergeInPlace, algo.isSorted
+	default:
+		algo := refCompareFunc[E](od.RefCompare)
+		s.search, s.lowerBound, s.upperBound, s.merge, s.isSorted =
+			algo.binarySearch, algo.lowerBound, algo.upperBound, algo.mergeInPlace, algo.isSorted
+	}
+	s.init()
+	return s
+}
+
+func (s *SortedSlice[E]) init() {
+	if len(s.list) > 1 && !s.isSorted(s.list) {
+		s.sortStable(s.list)
+	}
+}
+
+func (s *SortedSlice[E]) check() {
+	if debugEnabled && len(s.list) > 1 && !s.isSorted(s.list) {
+		panic("slices.SortedSlice: elements are out of order")
+	}
+}
+
+// Len returns the number of elements.
+func (s *SortedSlice[E]) Len() int {
+	return len(s.list)
+}
+
+// At returns the i-th smallest element.
+func (s *SortedSlice[E]) At(i int) E {
+	return s.list[i]
+}
+
+// Slice returns all elements in ascending order. It's a view of the inner
+// storage, should not be modified, and may be invalidated by modification
+// of the SortedSlice.
+func (s *SortedSlice[E]) Slice() []E {
+	return s.list
+}
+
+// Add inserts x after the elements equal to it. It's O(n) for moving
+// elements, use AddMany to add many elements together.
+func (s *SortedSlice[E]) Add(x E) {
+	s.list = Insert(s.list, s.upperBound(s.list, x), x)
+	s.check()
+}
+
+// AddMany inserts elements in list, equal elements are placed in the order
+// they are added. It sorts list, and then merges it into the SortedSlice
+// without extra memory.
+func (s *SortedSlice[E]) AddMany(list ...E) {
+	if len(list) == 0 {
+		return
+	}
+	border := len(s.list)
+	s.list = append(s.list, list...)
+	s.sortStable(s.list[border:])
+	if border != 0 {
+		s.merge(s.list, border)
+	}
+	s.check()
+}
+
+// Remove removes the first element equal to x, and reports whether there is
+// one.
+func (s *SortedSlice[E]) Remove(x E) bool {
+	i, found := s.search(s.list, x)
+	if !found {
+		return false
+	}
+	s.list = Delete(s.list, i, i+1)
+	s.clearTail(1)
+	s.check()
+	return true
+}
+
+// RemoveAll removes all elements equal to x, and returns the number of them.
+func (s *SortedSlice[E]) RemoveAll(x E) int {
+	return s.RemoveRange(s.EqualRange(x))
+}
+
+// RemoveRange removes elements at [lo, hi), and returns the number of them.
+func (s *SortedSlice[E]) RemoveRange(lo, hi int) int {
+	s.list = Delete(s.list, lo, hi)
+	s.clearTail(hi - lo)
+	s.check()
+	return hi - lo
+}
+
+// clearTail zeroes the n elements dropped from the tail of storage,
+// so objects they reference can be garbage collected.
+func (s *SortedSlice[E]) clearTail(n int) {
+	clear(s.list[len(s.list):][:n])
+}
+
+// Contains reports whether x is in the SortedSlice.
+func (s *SortedSlice[E]) Contains(x E) bool {
+	_, found := s.search(s.list, x)
+	return found
+}
+
+// Count returns the number of elements equal to x.
+func (s *SortedSlice[E]) Count(x E) int {
+	lo, hi := s.EqualRange(x)
+	return hi - lo
+}
+
+// Rank returns the number of elements less than x, which is also the
+// position of the first element not less than x.
+func (s *SortedSlice[E]) Rank(x E) int {
+	return s.lowerBound(s.list, x)
+}
+
+// EqualRange returns the range [lo, hi) of elements equal to x.
+func (s *SortedSlice[E]) EqualRange(x E) (lo, hi int) {
+	lo = s.lowerBound(s.list, x)
+	return lo, lo + s.upperBound(s.list[lo:], x)
+}
+
+// Range returns the range [lo, hi) of elements in [a, b).
+func (s *SortedSlice[E]) Range(a, b E) (lo, hi int) {
+	lo = s.lowerBound(s.list, a)
+	return lo, max(lo, s.lowerBound(s.list, b))
+}
@@ -0,0 +1,20 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build slicesdebug
+
+package slices
+
+import "testing"
+
+func TestSortedSliceCheck(t *testing.T) {
+	s := NewSortedSlice([]int{1, 2, 3, 4})
+	s.Slice()[0] = 5
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Add didn't panic on corrupted order")
+		}
+	}()
+	s.Add(0)
+}
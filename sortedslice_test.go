@@ -0,0 +1,109 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slices
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestSortedSlice(t *testing.T) {
+	n := 1000
+	data := make([]int, n)
+	for i := range data {
+		data[i] = rand.Intn(n / 4)
+	}
+	s := NewSortedSlice(Clone(data[:n/2]))
+	for _, v := range data[n/2 : n*3/4] {
+		s.Add(v)
+	}
+	s.AddMany(data[n*3/4:]...)
+	want := Clone(data)
+	Sort(want)
+	if !Equal(s.Slice(), want) || s.Len() != n {
+		t.Fatalf("got %v", s.Slice())
+	}
+
+	for x := -1; x <= n/4; x++ {
+		lo, hi := EqualRange(want, x)
+		if got := s.Rank(x); got != lo {
+			t.Errorf("Rank(%d) got %d, want %d", x, got, lo)
+		}
+		if got := s.Count(x); got != hi-lo {
+			t.Errorf("Count(%d) got %d, want %d", x, got, hi-lo)
+		}
+		if got := s.Contains(x); got != (lo < hi) {
+			t.Errorf("Contains(%d) got %v", x, got)
+		}
+		if lo < hi && s.At(lo) != x {
+			t.Errorf("At(%d) got %d, want %d", lo, s.At(lo), x)
+		}
+		a, b := s.Range(x, x+10)
+		if a != lo || b != LowerBound(want, x+10) {
+			t.Errorf("Range(%d, %d) got (%d, %d)", x, x+10, a, b)
+		}
+	}
+	if a, b := s.Range(10, 5); a != b {
+		t.Errorf("Range(10, 5) got (%d, %d)", a, b)
+	}
+
+	for _, v := range data[:n/2] {
+		if !s.Remove(v) {
+			t.Fatalf("Remove(%d) failed", v)
+		}
+		i, _ := BinarySearch(want, v)
+		want = Delete(want, i, i+1)
+	}
+	if s.Remove(-1) {
+		t.Errorf("Remove(-1) succeeded")
+	}
+	if !Equal(s.Slice(), want) {
+		t.Fatalf("got %v after Remove", s.Slice())
+	}
+	x := want[len(want)/2]
+	lo, hi := EqualRange(want, x)
+	if got := s.RemoveAll(x); got != hi-lo {
+		t.Errorf("RemoveAll(%d) got %d, want %d", x, got, hi-lo)
+	}
+	want = Delete(want, lo, hi)
+	if !Equal(s.Slice(), want) || s.Contains(x) {
+		t.Fatalf("got %v after RemoveAll", s.Slice())
+	}
+}
+
+func TestSortedSliceStability(t *testing.T) {
+	n := 1000
+	data := make(intPairs, n)
+	for i := range data {
+		data[i].a = rand.Intn(n / 10)
+	}
+	data.initB()
+	s := intPairOrder.NewSortedSlice(nil)
+	for _, v := range data[:n/3] {
+		s.Add(v)
+	}
+	s.AddMany(data[n/3 : n*2/3]...)
+	s.AddMany(data[n*2/3:]...)
+	got := intPairs(s.Slice())
+	if !intPairOrder.IsSorted(got) || !got.inOrder() || len(got) != n {
+		t.Errorf("not stable")
+	}
+
+	// Remove drops the first one of equal elements.
+	want := Clone(got)
+	x := data[n/2]
+	i, _ := intPairOrder.BinarySearch(want, x)
+	want = Delete(want, i, i+1)
+	if !s.Remove(x) || !Equal(s.Slice(), want) {
+		t.Errorf("Remove(%v) dropped wrong element", x)
+	}
+
+	unsorted := Clone(data)
+	s = intPairOrder.NewSortedSlice(unsorted)
+	got = intPairs(s.Slice())
+	if !intPairOrder.IsSorted(got) || !got.inOrder() {
+		t.Errorf("NewSortedSlice didn't sort stably")
+	}
+}
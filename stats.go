@@ -0,0 +1,173 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slices
+
+import (
+	"strconv"
+	"unsafe"
+)
+
+// Strategy is the way chosen by Order.SortWithStats.
+type Strategy uint8
+
+const (
+	StrategyNone             Strategy = iota // nothing to do with less than 2 elements
+	StrategyIntroSort                        // introsort on elements
+	StrategyRefIntroSort                     // introsort on pointers, then reorder elements
+	StrategyMergeSort                        // adaptive merge sort with buffer
+	StrategyRefMergeSort                     // adaptive merge sort on pointers, then reorder elements
+	StrategyInplaceMergeSort                 // adaptive merge sort without extra memory
+)
+
+var strategyNames = [...]string{
+	StrategyNone:             "None",
+	StrategyIntroSort:        "IntroSort",
+	StrategyRefIntroSort:     "RefIntroSort",
+	StrategyMergeSort:        "MergeSort",
+	StrategyRefMergeSort:     "RefMergeSort",
+	StrategyInplaceMergeSort: "InplaceMergeSort",
+}
+
+func (s Strategy) String() string {
+	if int(s) < len(strategyNames) {
+		return strategyNames[s]
+	}
+	return "Strategy(" + strconv.Itoa(int(s)) + ")"
+}
+
+// Stats records what a sort call has done.
+// Swaps only counts exchanges in partitioning and heap sort, moves in
+// insertion and merging are not counted.
+type Stats struct {
+	Strategy    Strategy
+	ByRef       bool // compare by pointer, RefLess or RefCompare is chosen
+	NoRefSort   bool // sorting by pointer list is rejected as too costly
+	Presorted   bool // sorted or reversed input is detected and handled in O(n)
+	Comparisons int
+	Swaps       int
+	MaxDepth    int // deepest level of partitioning
+	HeapSorts   int // times of falling back to heap sort
+	Allocs      int // times of allocating extra memory
+	AllocBytes  int // total size of extra memory
+
+	chanceTop, chanceBottom int
+}
+
+func (s *Stats) addAlloc(bytes int) {
+	if s != nil {
+		s.Allocs++
+		s.AllocBytes += bytes
+	}
+}
+
+// SortWithStats works like SortWithOption, and fills stats with what it does.
+// It runs an instrumented version of the algorithms, which is slower, so it's
+// for diagnosis. Other methods are not affected.
+func (od *Order[E]) SortWithStats(list []E, stable, inplace bool, stats *Stats) {
+	*stats = Stats{}
+	if len(list) < 2 {
+		return
+	}
+	x := od.derive()
+	stats.Strategy = od.strategy(list, stable, inplace, stats)
+	if kind := od.kind(); kind == kindCompare || kind == kindRefCompare {
+		// It runs the three-way specialization like the others.
+		compare := x.RefCompare
+		algo := &statsCompareOrder[E]{compareFn: compare, stats: stats}
+		refAlgo := &statsCompareOrder[*E]{
+			compareFn: func(a, b **E) int { return compare(*a, *b) },
+			stats:     stats,
+		}
+		sortWithStats(list, stable, stats, algo.sortFast, algo.sortStable,
+			refAlgo.sortFast, refAlgo.sortStable)
+		return
+	}
+	less := x.RefLess
+	algo := &statsOrder[E]{lessFn: less, stats: stats}
+	refAlgo := &statsOrder[*E]{
+		lessFn: func(a, b **E) bool { return less(*a, *b) },
+		stats:  stats,
+	}
+	sortWithStats(list, stable, stats, algo.sortFast, algo.sortStable,
+		refAlgo.sortFast, refAlgo.sortStable)
+}
+
+// sortWithStats runs the algorithms by the strategy in stats.
+func sortWithStats[E any](list []E, stable bool, stats *Stats,
+	sortFast func([]E), sortStable func([]E, bool),
+	refSortFast func([]*E), refSortStable func([]*E, bool)) {
+	switch stats.Strategy {
+	case StrategyIntroSort:
+		sortFast(list)
+	case StrategyMergeSort:
+		sortStable(list, false)
+	case StrategyInplaceMergeSort:
+		sortStable(list, true)
+	default:
+		ref := refsOf(list)
+		stats.addAlloc(len(ref) * int(unsafe.Sizeof(ref[0])))
+		if stable {
+			refSortStable(ref, false)
+		} else {
+			refSortFast(ref)
+		}
+		reorder(list, ref)
+	}
+}
+
+// statsOrder is the instrumented specialization in zfunc_f.go, it compares
+// like refLessFunc.
+type statsOrder[E any] struct {
+	lessFn func(a, b *E) bool
+	stats  *Stats
+}
+
+func (lt *statsOrder[E]) less(a, b *E) bool {
+	lt.stats.Comparisons++
+	return lt.lessFn(a, b)
+}
+
+func (lt *statsOrder[E]) observe(ev sortEvent, arg int) {
+	observeStats[E](lt.stats, ev, arg)
+}
+
+// statsCompareOrder is the instrumented specialization in zfunc_g.go, it
+// compares like refCompareFunc.
+type statsCompareOrder[E any] struct {
+	compareFn func(a, b *E) int
+	stats     *Stats
+}
+
+func (lt *statsCompareOrder[E]) compare(a, b *E) int {
+	lt.stats.Comparisons++
+	return lt.compareFn(a, b)
+}
+
+func (lt *statsCompareOrder[E]) observe(ev sortEvent, arg int) {
+	observeStats[E](lt.stats, ev, arg)
+}
+
+func observeStats[E any](st *Stats, ev sortEvent, arg int) {
+	switch ev {
+	case eventPresorted:
+		st.Presorted = true
+	case eventPartition:
+		// The remaining chance decreases with depth, and the top level
+		// partition has the greatest one.
+		if st.MaxDepth == 0 {
+			st.chanceTop, st.chanceBottom = arg, arg
+		}
+		st.chanceTop = max(st.chanceTop, arg)
+		st.chanceBottom = min(st.chanceBottom, arg)
+		st.MaxDepth = st.chanceTop - st.chanceBottom + 1
+	case eventHeapSort:
+		st.HeapSorts++
+	case eventSwap:
+		st.Swaps += arg
+	case eventAlloc:
+		var elem E
+		st.addAlloc(arg * int(unsafe.Sizeof(elem)))
+	}
+}
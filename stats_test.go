@@ -0,0 +1,150 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slices
+
+import (
+	"cmp"
+	"math/rand"
+	"testing"
+	"unsafe"
+)
+
+func TestSortWithStats(t *testing.T) {
+	n := 10000
+	ncmp := 0
+	od := Order[int]{
+		Less: func(a, b int) bool {
+			ncmp++
+			return a < b
+		}}
+	data := make([]int, n)
+	for i := range data {
+		data[i] = rand.Intn(n)
+	}
+	want := Clone(data)
+	Sort(want)
+
+	cases := []struct {
+		stable, inplace bool
+		strategy        Strategy
+		allocs          int
+	}{
+		{false, false, StrategyIntroSort, 0},
+		{false, true, StrategyIntroSort, 0},
+		{true, false, StrategyMergeSort, 1},
+		{true, true, StrategyInplaceMergeSort, 0},
+	}
+	var stats Stats
+	for _, tt := range cases {
+		list := Clone(data)
+		ncmp = 0
+		od.SortWithStats(list, tt.stable, tt.inplace, &stats)
+		if !Equal(list, want) {
+			t.Errorf("stable=%v, inplace=%v: didn't sort", tt.stable, tt.inplace)
+		}
+		if stats.Strategy != tt.strategy || stats.ByRef || stats.Presorted ||
+			stats.Allocs != tt.allocs || stats.HeapSorts != 0 {
+			t.Errorf("stable=%v, inplace=%v: unexpected stats %+v", tt.stable, tt.inplace, stats)
+		}
+		if stats.Comparisons != ncmp {
+			t.Errorf("stable=%v, inplace=%v: %d comparisons reported, %d happened",
+				tt.stable, tt.inplace, stats.Comparisons, ncmp)
+		}
+		if !tt.stable && (stats.Swaps == 0 || stats.MaxDepth < 2 ||
+			stats.MaxDepth > log2Ceil(uint(n))*3/2+1) {
+			t.Errorf("stable=%v, inplace=%v: unexpected stats %+v", tt.stable, tt.inplace, stats)
+		}
+
+		ncmp = 0
+		od.SortWithStats(list, tt.stable, tt.inplace, &stats)
+		if !stats.Presorted || stats.Comparisons != ncmp || stats.Swaps != 0 {
+			t.Errorf("stable=%v, inplace=%v: unexpected stats on sorted list %+v", tt.stable, tt.inplace, stats)
+		}
+	}
+
+	adv, list := newAdversary(n)
+	(&Order[int]{Less: adv.less}).SortWithStats(list, false, false, &stats)
+	if stats.HeapSorts == 0 || stats.Comparisons != adv.count {
+		t.Errorf("unexpected stats on adversary input %+v", stats)
+	}
+
+	od.SortWithStats(list[:1], false, false, &stats)
+	if stats != (Stats{}) {
+		t.Errorf("unexpected stats on single element %+v", stats)
+	}
+}
+
+func TestSortWithStatsByRef(t *testing.T) {
+	n := 1000
+	data := make([]bigObject, n)
+	for i := range data {
+		data[i].val = rand.Intn(n)
+	}
+	od := Order[bigObject]{
+		RefLess: func(a, b *bigObject) bool {
+			return a.val < b.val
+		}}
+	var stats Stats
+	for _, stable := range []bool{false, true} {
+		list := Clone(data)
+		od.SortWithStats(list, stable, false, &stats)
+		if !od.IsSorted(list) {
+			t.Errorf("stable=%v: didn't sort", stable)
+		}
+		strategy, allocs := StrategyRefIntroSort, 1
+		if stable {
+			strategy, allocs = StrategyRefMergeSort, 2
+		}
+		if stats.Strategy != strategy || !stats.ByRef || stats.NoRefSort ||
+			stats.Allocs != allocs || stats.AllocBytes != n*int(unsafe.Sizeof(&data[0]))*(allocs+1)/2 {
+			t.Errorf("stable=%v: unexpected stats %+v", stable, stats)
+		}
+	}
+
+	od.SortWithStats(data, false, true, &stats)
+	if stats.Strategy != StrategyIntroSort || stats.Allocs != 0 {
+		t.Errorf("inplace: unexpected stats %+v", stats)
+	}
+	if s := StrategyRefMergeSort.String(); s != "RefMergeSort" {
+		t.Errorf("unexpected name %q", s)
+	}
+}
+
+func TestSortWithStatsCompare(t *testing.T) {
+	n := 10000
+	data := make([]int, n)
+	for i := range data {
+		data[i] = rand.Intn(n)
+	}
+	ncmp := 0
+	od := Order[int]{
+		Less: func(a, b int) bool {
+			ncmp++
+			return a < b
+		},
+		Compare: func(a, b int) int {
+			ncmp++
+			return cmp.Compare(a, b)
+		}}
+	var stats Stats
+	for _, stable := range []bool{false, true} {
+		// Stats should describe the three-way specialization SortWithOption runs.
+		list := Clone(data)
+		ncmp = 0
+		od.SortWithOption(list, stable, false)
+		want := ncmp
+
+		list = Clone(data)
+		ncmp = 0
+		od.SortWithStats(list, stable, false, &stats)
+		if !IsSorted(list) {
+			t.Errorf("stable=%v: didn't sort", stable)
+		}
+		if stats.Comparisons != ncmp || ncmp != want {
+			t.Errorf("stable=%v: %d comparisons reported, %d happened, %d by SortWithOption",
+				stable, stats.Comparisons, ncmp, want)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slices
+
+import (
+	"cmp"
+)
+
+// TopK keeps the greatest k elements of a stream with O(k) memory.
+// The one created by NewBottomK keeps the smallest ones instead.
+type TopK[E any] struct {
+	k    int
+	heap []E
+	push func(heap []E, k int, x E) []E
+	sort func(list []E)
+}
+
+// NewTopK creates a TopK which keeps the greatest k elements.
+// It panics if k is negative.
+func NewTopK[E cmp.Ordered](k int) *TopK[E] {
+	if k < 0 {
+		panic("slices.NewTopK: negative k")
+	}
+	return &TopK[E]{
+		k:    k,
+		heap: make([]E, 0, k),
+		push: pushGreatest[E],
+		sort: sortFast[E],
+	}
+}
+
+// NewBottomK creates a TopK which keeps the smallest k elements.
+// It panics if k is negative.
+func NewBottomK[E cmp.Ordered](k int) *TopK[E] {
+	if k < 0 {
+		panic("slices.NewBottomK: negative k")
+	}
+	return &TopK[E]{
+		k:    k,
+		heap: make([]E, 0, k),
+		push: pushBounded[E],
+		sort: sortFast[E],
+	}
+}
+
+// The general version of NewTopK.
+func (od *Order[E]) NewTopK(k int) *TopK[E] {
+	if k < 0 {
+		panic("slices.NewTopK: negative k")
+	}
+	t := &TopK[E]{k: k, heap: make([]E, 0, k)}
+	switch od.kind() {
+	case kindLess:
+		algo := lessFunc[E](od.Less)
+		t.push, t.sort = algo.pushGreatest, algo.sortFast
+	case kindRefLess:
+		algo := refLessFunc[E](od.RefLess)
+		t.push, t.sort = algo.pushGreatest, algo.sortFast
+	case kindCompare:
+		algo := compareFunc[E](od.Compare)
+		t.push, t.sort = algo.pushGreatest, algo.sortFast
+	default:
+		algo := refCompareFunc[E](od.RefCompare)
+		t.push, t.sort = algo.pushGreatest, algo.sortFast
+	}
+	return t
+}
+
+// The general version of NewBottomK.
+func (od *Order[E]) NewBottomK(k int) *TopK[E] {
+	if k < 0 {
+		panic("slices.NewBottomK: negative k")
+	}
+	t := &TopK[E]{k: k, heap: make([]E, 0, k)}
+	switch od.kind() {
+	case kindLess:
+		algo := lessFunc[E](od.Less)
+		t.push, t.sort = algo.pushBounded, algo.sortFast
+	case kindRefLess:
+		algo := refLessFunc[E](od.RefLess)
+		t.push, t.sort = algo.pushBounded, algo.sortFast
+	case kindCompare:
+		algo := compareFunc[E](od.Compare)
+		t.push, t.sort = algo.pushBounded, algo.sortFast
+	default:
+		algo := refCompareFunc[E](od.RefCompare)
+		t.push, t.sort = algo.pushBounded, algo.sortFast
+	}
+	return t
+}
+
+// K returns the capacity of the TopK.
+func (t *TopK[E]) K() int {
+	return t.k
+}
+
+// Len returns the number of elements kept, which is at most K.
+func (t *TopK[E]) Len() int {
+	return len(t.heap)
+}
+
+// Push adds an element.
+func (t *TopK[E]) Push(x E) {
+	t.heap = t.push(t.heap, t.k, x)
+}
+
+// PushSlice adds all elements in list.
+func (t *TopK[E]) PushSlice(list []E) {
+	for i := 0; i < len(list); i++ {
+		t.heap = t.push(t.heap, t.k, list[i])
+	}
+}
+
+// Merge adds the elements kept by other, so accumulators fed by different
+// goroutines can be combined. other is not modified.
+func (t *TopK[E]) Merge(other *TopK[E]) {
+	list := other.heap
+	if other == t {
+		list = Clone(list)
+	}
+	t.PushSlice(list)
+}
+
+// Result returns the elements kept in ascending order.
+// The TopK is still usable after that.
+func (t *TopK[E]) Result() []E {
+	out := Clone(t.heap)
+	t.sort(out)
+	return out
+}
+
+// Reset drops all elements kept.
+func (t *TopK[E]) Reset() {
+	clear(t.heap)
+	t.heap = t.heap[:0]
+}
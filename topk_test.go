@@ -0,0 +1,135 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slices
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestTopK(t *testing.T) {
+	n := 10000
+	data := make([]int, n)
+	for i := range data {
+		data[i] = rand.Intn(n / 2)
+	}
+	sorted := Clone(data)
+	Sort(sorted)
+
+	for _, k := range []int{0, 1, 2, 10, 100, n, n + 10} {
+		want := sorted[n-min(k, n):]
+		tk := NewTopK[int](k)
+		for _, v := range data[:n/2] {
+			tk.Push(v)
+		}
+		tk.Result() // It should not break the heap.
+		for _, v := range data[n/2:] {
+			tk.Push(v)
+		}
+		if got := tk.Result(); !Equal(got, want) || tk.Len() != len(want) {
+			t.Errorf("k=%d: got %v", k, got)
+		}
+
+		tk = intOrder.NewTopK(k)
+		tk.PushSlice(data)
+		if got := tk.Result(); !Equal(got, want) {
+			t.Errorf("k=%d: got %v by Order", k, got)
+		}
+		tk.Reset()
+		if tk.Len() != 0 || tk.K() != k {
+			t.Errorf("k=%d: Len %d, K %d after Reset", k, tk.Len(), tk.K())
+		}
+
+		want = sorted[:min(k, n)]
+		tk = NewBottomK[int](k)
+		tk.PushSlice(data)
+		if got := tk.Result(); !Equal(got, want) {
+			t.Errorf("k=%d: got %v from BottomK", k, got)
+		}
+		tk = intOrder.NewBottomK(k)
+		tk.PushSlice(data)
+		if got := tk.Result(); !Equal(got, want) {
+			t.Errorf("k=%d: got %v from BottomK by Order", k, got)
+		}
+	}
+}
+
+func TestTopKMerge(t *testing.T) {
+	n, k := 10000, 100
+	data := make([]int, n)
+	for i := range data {
+		data[i] = rand.Int()
+	}
+	sorted := Clone(data)
+	Sort(sorted)
+
+	shards := make([]*TopK[int], 8)
+	for i := range shards {
+		shards[i] = NewTopK[int](k)
+	}
+	for _, v := range data {
+		shards[rand.Intn(len(shards))].Push(v)
+	}
+	all := NewTopK[int](k)
+	for _, shard := range shards {
+		all.Merge(shard)
+	}
+	if got := all.Result(); !Equal(got, sorted[n-k:]) {
+		t.Errorf("got %v", got)
+	}
+
+	all.Merge(all)
+	want := make([]int, 0, k)
+	for i := n - 1; len(want) < k; i-- {
+		want = append(want, sorted[i], sorted[i])
+	}
+	Sort(want)
+	if got := all.Result(); !Equal(got, want) {
+		t.Errorf("got %v after merging itself", got)
+	}
+}
+
+func TestTopKGreatest(t *testing.T) {
+	n, k := 10000, 10
+	data := make([]intPair, n)
+	for i := range data {
+		data[i] = intPair{rand.Intn(n), i}
+	}
+	tk := intPairOrder.NewTopK(k)
+	tk.PushSlice(data)
+	got := tk.Result()
+	intPairOrder.Sort(data)
+	for i := 0; i < k; i++ {
+		if got[i].a != data[n-k+i].a {
+			t.Fatalf("got %v", got)
+		}
+	}
+	tk = intPairOrder.NewBottomK(k)
+	tk.PushSlice(data)
+	got = tk.Result()
+	for i := 0; i < k; i++ {
+		if got[i].a != data[i].a {
+			t.Fatalf("got %v from BottomK", got)
+		}
+	}
+}
+
+func TestTopKPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("NewTopK with negative k didn't panic")
+		}
+	}()
+	NewTopK[int](-1)
+}
+
+func TestBottomKPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("NewBottomK with negative k didn't panic")
+		}
+	}()
+	NewBottomK[int](-1)
+}
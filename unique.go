@@ -0,0 +1,75 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slices
+
+import (
+	"cmp"
+)
+
+// Unique sorts list and removes duplicate elements, then returns the
+// shortened slice. Unlike Compact, all NaNs are regarded as equal.
+// Like Compact, it might not modify the discarded elements at the tail.
+func Unique[E cmp.Ordered](list []E) []E {
+	Sort(list)
+	list, _ = compactSorted(list, nil, false)
+	return list
+}
+
+// UniqueCount is like Unique, but it also reports how many times each unique
+// element appears in the original list.
+func UniqueCount[E cmp.Ordered](list []E) ([]E, []int) {
+	Sort(list)
+	return compactSorted(list, nil, true)
+}
+
+// The general version of Unique.
+// Which one of the equal elements is kept is unspecified.
+func (od *Order[E]) Unique(list []E) []E {
+	od.Sort(list)
+	switch od.kind() {
+	case kindLess:
+		list, _ = lessFunc[E](od.Less).compactSorted(list, nil, false)
+	case kindRefLess:
+		list, _ = refLessFunc[E](od.RefLess).compactSorted(list, nil, false)
+	case kindCompare:
+		list, _ = compareFunc[E](od.Compare).compactSorted(list, nil, false)
+	default:
+		list, _ = refCompareFunc[E](od.RefCompare).compactSorted(list, nil, false)
+	}
+	return list
+}
+
+// UniqueStable is like Unique, but the first one in original order is kept
+// for each group of equal elements.
+func (od *Order[E]) UniqueStable(list []E) []E {
+	od.SortStable(list)
+	switch od.kind() {
+	case kindLess:
+		list, _ = lessFunc[E](od.Less).compactSorted(list, nil, false)
+	case kindRefLess:
+		list, _ = refLessFunc[E](od.RefLess).compactSorted(list, nil, false)
+	case kindCompare:
+		list, _ = compareFunc[E](od.Compare).compactSorted(list, nil, false)
+	default:
+		list, _ = refCompareFunc[E](od.RefCompare).compactSorted(list, nil, false)
+	}
+	return list
+}
+
+// The general version of UniqueCount.
+// The first one in original order is kept for each group of equal elements.
+func (od *Order[E]) UniqueCount(list []E) ([]E, []int) {
+	od.SortStable(list)
+	switch od.kind() {
+	case kindLess:
+		return lessFunc[E](od.Less).compactSorted(list, nil, true)
+	case kindRefLess:
+		return refLessFunc[E](od.RefLess).compactSorted(list, nil, true)
+	case kindCompare:
+		return compareFunc[E](od.Compare).compactSorted(list, nil, true)
+	default:
+		return refCompareFunc[E](od.RefCompare).compactSorted(list, nil, true)
+	}
+}
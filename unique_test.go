@@ -0,0 +1,87 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slices
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestUnique(t *testing.T) {
+	for _, n := range []int{0, 1, 10, 1000, radixSize * 2} {
+		data := make([]int, n)
+		for i := range data {
+			data[i] = rand.Intn(n/2 + 1)
+		}
+		want := Clone(data)
+		Sort(want)
+		want = Compact(want)
+
+		if got := Unique(Clone(data)); !Equal(got, want) {
+			t.Errorf("Unique mismatch with Sort and Compact on %d ints", n)
+		}
+		if got := intCompareOrder.Unique(Clone(data)); !Equal(got, want) {
+			t.Errorf("Order.Unique mismatch with Sort and Compact on %d ints", n)
+		}
+
+		got, counts := UniqueCount(Clone(data))
+		if !Equal(got, want) || len(counts) != len(got) {
+			t.Fatalf("UniqueCount mismatch with Sort and Compact on %d ints", n)
+		}
+		cnt := make(map[int]int)
+		for _, v := range data {
+			cnt[v]++
+		}
+		for i, v := range got {
+			if counts[i] != cnt[v] {
+				t.Fatalf("UniqueCount got count %d for %d, want %d", counts[i], v, cnt[v])
+			}
+		}
+	}
+
+	floats := []float64{math.NaN(), 1, math.NaN(), 0, 1, math.Copysign(0, -1)}
+	got, counts := UniqueCount(floats)
+	if len(got) != 3 || !math.IsNaN(got[0]) || got[1] != 0 || got[2] != 1 ||
+		!Equal(counts, []int{2, 2, 2}) {
+		t.Errorf("UniqueCount got %v, %v", got, counts)
+	}
+}
+
+func TestUniqueStable(t *testing.T) {
+	data := make(intPairs, 10000)
+	for i := range data {
+		data[i].a = rand.Intn(100)
+	}
+	data.initB()
+	first := make(map[int]int)
+	for _, v := range data {
+		if _, ok := first[v.a]; !ok {
+			first[v.a] = v.b
+		}
+	}
+	od := OrderBy(func(v *intPair) int { return v.a })
+	got := od.UniqueStable(Clone(data))
+	if len(got) != len(first) || !od.IsSorted(got) {
+		t.Fatalf("UniqueStable got %d elements, want %d", len(got), len(first))
+	}
+	for _, v := range got {
+		if first[v.a] != v.b {
+			t.Errorf("UniqueStable kept %v, want the first one %d", v, first[v.a])
+		}
+	}
+
+	got, counts := intPairOrder.UniqueCount(Clone(data))
+	sum := 0
+	for i, v := range got {
+		if first[v.a] != v.b {
+			t.Errorf("UniqueCount kept %v, want the first one %d", v, first[v.a])
+		}
+		sum += counts[i]
+	}
+	if sum != len(data) {
+		t.Errorf("UniqueCount got counts sum %d, want %d", sum, len(data))
+	}
+}
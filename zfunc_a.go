@@ -22,6 +22,37 @@ func (lt lessFunc[E]) binarySearch(list []E, x E) (int, bool) {
 	return a, true
 }
 
+func (lt lessFunc[E]) lowerBound(list []E, x E) int {
+	a, b := 0, len(list)
+	for a < b {
+		m := int(uint(a+b) / 2)
+		if lt(list[m], x) {
+			a = m + 1
+		} else {
+			b = m
+		}
+	}
+	return a
+}
+
+func (lt lessFunc[E]) upperBound(list []E, x E) int {
+	a, b := 0, len(list)
+	for a < b {
+		m := int(uint(a+b) / 2)
+		if lt(x, list[m]) {
+			b = m
+		} else {
+			a = m + 1
+		}
+	}
+	return a
+}
+
+func (lt lessFunc[E]) equalRange(list []E, x E) (int, int) {
+	a := lt.lowerBound(list, x)
+	return a, a + lt.gallopUpper(list[a:], x)
+}
+
 func (lt lessFunc[E]) isSorted(list []E) bool {
 	for i := 1; i < len(list); i++ {
 		if lt(list[i], list[i-1]) {
@@ -74,9 +105,11 @@ func (lt lessFunc[E]) sortFast(list []E) {
 			hint = hintSorted
 		}
 		if hint == hintSorted && lt.isSorted(list) {
+			observe[E](eventPresorted, 0)
 			return
 		}
 
+		observe[E](eventPartition, chance)
 		l, r := 0, size-1
 		for {
 			for lt(list[l], pivot) {
@@ -89,6 +122,7 @@ func (lt lessFunc[E]) sortFast(list []E) {
 				break
 			}
 			list[l], list[r] = list[r], list[l]
+			observe[E](eventSwap, 1)
 			l++
 			r--
 		}
@@ -126,35 +160,158 @@ func (lt lessFunc[E]) median(list []E, a, b, c int) (int, uint8) {
 }
 
 func (lt lessFunc[E]) sortStable(list []E, inplace bool) {
-	if size := len(list); size < 16 {
-		lt.simpleSort(list)
-	} else if inplace {
-		step := 8
-		a, b := 0, step
-		for b <= size {
-			lt.simpleSort(list[a:b])
-			a = b
-			b += step
-		}
-		lt.simpleSort(list[a:])
-
-		for step < size {
-			a, b = 0, step*2
-			for b <= size {
-				lt.symmerge(list[a:b], step)
-				a = b
-				b += step * 2
-			}
-			if a+step < size {
-				lt.symmerge(list[a:], step)
-			}
-			step *= 2
+	size := len(list)
+	b := lt.extendRun(list)
+	if b == size {
+		observe[E](eventPresorted, 0)
+		return
+	}
+	var buf []E
+	if !inplace {
+		buf = make([]E, size/2)
+		observe[E](eventAlloc, size/2)
+	}
+	powerMerge(size, b, func(a int) int {
+		return a + lt.extendRun(list[a:])
+	}, func(a, b, c int) bool {
+		lt.mergeRuns(list[a:c], b-a, buf)
+		return true
+	})
+}
+
+func (lt lessFunc[E]) extendRun(list []E) int {
+	n := min(len(list), 2)
+	if n < 2 {
+		return n
+	}
+	if lt(list[1], list[0]) {
+		for n < len(list) && lt(list[n], list[n-1]) {
+			n++
+		}
+		Reverse(list[:n])
+	} else {
+		for n < len(list) && !lt(list[n], list[n-1]) {
+			n++
 		}
+	}
+
+	for end := min(len(list), stableMinRun); n < end; n++ {
+		curr := list[n]
+		pos := n
+		for ; pos > 0 && lt(curr, list[pos-1]); pos-- {
+			list[pos] = list[pos-1]
+		}
+		list[pos] = curr
+	}
+	return n
+}
+
+func (lt lessFunc[E]) mergeRuns(list []E, border int, buf []E) {
+	if buf == nil {
+		lt.mergeInPlace(list, border)
+		return
+	}
+	a := lt.gallopUpper(list[:border], list[border])
+	if a == border {
+		return
+	}
+	b := len(list) - lt.gallopLowerTail(list[border:], list[border-1])
+	list, border = list[a:b], border-a
+	if border <= len(list)-border {
+		lt.mergeLow(list, border, buf)
 	} else {
-		temp := make([]E, size)
-		copy(temp, list)
-		lt.mergeSort(temp, list)
+		lt.mergeHigh(list, border, buf)
+	}
+}
+
+func (lt lessFunc[E]) mergeLow(list []E, border int, buf []E) {
+	left := buf[:border]
+	copy(left, list[:border])
+	i, j, k := 0, border, 0
+	wl, wr := 0, 0
+	for i < len(left) && j < len(list) {
+		if lt(list[j], left[i]) {
+			list[k] = list[j]
+			j++
+			k++
+			wl = 0
+			if wr++; wr >= stableMinGallop {
+				n := lt.gallopLower(list[j:], left[i])
+				copy(list[k:], list[j:j+n])
+				j += n
+				k += n
+				wr = 0
+			}
+		} else {
+			list[k] = left[i]
+			i++
+			k++
+			wr = 0
+			if wl++; wl >= stableMinGallop {
+				n := lt.gallopUpper(left[i:], list[j])
+				copy(list[k:], left[i:i+n])
+				i += n
+				k += n
+				wl = 0
+			}
+		}
+	}
+	copy(list[k:], left[i:])
+}
+
+func (lt lessFunc[E]) mergeHigh(list []E, border int, buf []E) {
+	right := buf[:len(list)-border]
+	copy(right, list[border:])
+	i, j, k := border-1, len(right)-1, len(list)-1
+	wl, wr := 0, 0
+	for i >= 0 && j >= 0 {
+		if lt(right[j], list[i]) {
+			list[k] = list[i]
+			i--
+			k--
+			wr = 0
+			if wl++; wl >= stableMinGallop {
+				n := lt.gallopUpperTail(list[:i+1], right[j])
+				copy(list[k-n+1:k+1], list[i-n+1:i+1])
+				i -= n
+				k -= n
+				wl = 0
+			}
+		} else {
+			list[k] = right[j]
+			j--
+			k--
+			wl = 0
+			if wr++; wr >= stableMinGallop {
+				n := lt.gallopLowerTail(right[:j+1], list[i])
+				copy(list[k-n+1:k+1], right[j-n+1:j+1])
+				j -= n
+				k -= n
+				wr = 0
+			}
+		}
+	}
+	copy(list[:j+1], right[:j+1])
+}
+
+func (lt lessFunc[E]) gallopLowerTail(list []E, x E) int {
+	n := len(list)
+	a, b := 0, 1
+	for b <= n && !lt(list[n-b], x) {
+		a, b = b, b*2
 	}
+	c := n - min(b-1, n)
+	return n - c - lt.lowerBound(list[c:n-a], x)
+}
+
+func (lt lessFunc[E]) gallopUpperTail(list []E, x E) int {
+	n := len(list)
+	a, b := 0, 1
+	for b <= n && lt(x, list[n-b]) {
+		a, b = b, b*2
+	}
+	c := n - min(b-1, n)
+	return n - c - lt.upperBound(list[c:n-a], x)
 }
 
 func (lt lessFunc[E]) partlySort(list []E, k int) {
@@ -198,6 +355,7 @@ func (lt lessFunc[E]) heapSort(list []E) {
 		list[0], list[end] = list[end], list[0]
 		lt.heapDown(list[:end], 0)
 	}
+	observe[E](eventSwap, len(list)-1)
 }
 
 func (lt lessFunc[E]) heapDown(list []E, pos int) {
@@ -214,7 +372,8 @@ func (lt lessFunc[E]) heapDown(list []E, pos int) {
 		pos, kid = kid, kid*2+1
 	}
 	if kid == last && lt(curr, list[kid]) {
-		list[pos], pos = list[kid], kid
+		list[pos] = list[kid]
+		pos = kid
 	}
 	list[pos] = curr
 }
@@ -283,12 +442,16 @@ func (lt lessFunc[E]) triPartition(list []E) (l, r int) {
 	m, s := size/2, size/4
 
 	x, l, _, r, y := lt.sortIndex5(list, m-s, m-1, m, m+1, m+s)
+	return lt.dualPartition(list, x, l, r, y)
+}
 
-	s = size - 1
+func (lt lessFunc[E]) dualPartition(list []E, x, l, r, y int) (int, int) {
+	s := len(list) - 1
 	pivotL, pivotR := list[l], list[r]
 	list[l], list[r] = list[0], list[s]
 	list[1], list[x] = list[x], list[1]
 	list[s-1], list[y] = list[y], list[s-1]
+	observe[E](eventSwap, 4)
 
 	l, r = 2, s-2
 	for {
@@ -300,6 +463,7 @@ func (lt lessFunc[E]) triPartition(list []E) (l, r int) {
 		}
 		if lt(pivotR, list[l]) {
 			list[l], list[r] = list[r], list[l]
+			observe[E](eventSwap, 1)
 			r--
 			if lt(list[l], pivotL) {
 				l++
@@ -319,13 +483,16 @@ func (lt lessFunc[E]) triPartition(list []E) (l, r int) {
 			}
 			if lt(list[r], pivotL) {
 				list[l], list[k], list[r] = list[r], list[l], list[k]
+				observe[E](eventSwap, 2)
 				l++
 			} else {
 				list[k], list[r] = list[r], list[k]
+				observe[E](eventSwap, 1)
 			}
 			r--
 		} else if lt(list[k], pivotL) {
 			list[k], list[l] = list[l], list[k]
+			observe[E](eventSwap, 1)
 			l++
 		}
 	}
@@ -334,11 +501,18 @@ func (lt lessFunc[E]) triPartition(list []E) (l, r int) {
 	r++
 	list[0], list[l] = list[l], pivotL
 	list[s], list[r] = list[r], pivotR
+	observe[E](eventSwap, 2)
 	return l, r
 }
 
 func (lt lessFunc[E]) partlySelect(list []E, k int) {
+	chance := log2Ceil(uint(len(list))) * 3 / 2
 	for len(list) > 14 {
+		if chance--; chance < 0 {
+			observe[E](eventHeapSort, len(list))
+			lt.heapSelect(list, k)
+			return
+		}
 		l, r := lt.triPartition(list)
 		switch {
 		case k <= l:
@@ -358,12 +532,29 @@ func (lt lessFunc[E]) partlySelect(list []E, k int) {
 	lt.simpleSort(list)
 }
 
+func (lt lessFunc[E]) heapSelect(list []E, k int) {
+	heap := list[:k]
+	for idx := k/2 - 1; idx >= 0; idx-- {
+		lt.heapDown(heap, idx)
+	}
+	for i := k; i < len(list); i++ {
+		if lt(list[i], heap[0]) {
+			heap[0], list[i] = list[i], heap[0]
+			observe[E](eventSwap, 1)
+			lt.heapDown(heap, 0)
+		}
+	}
+	heap[0], heap[k-1] = heap[k-1], heap[0]
+}
+
 func (lt lessFunc[E]) introSort(list []E, chance int) {
 	for len(list) > 14 {
 		if chance--; chance < 0 {
+			observe[E](eventHeapSort, len(list))
 			lt.heapSort(list)
 			return
 		}
+		observe[E](eventPartition, chance)
 
 		l, r := lt.triPartition(list)
 		lt.introSort(list[:l], chance)
@@ -489,3 +680,589 @@ func (lt lessFunc[E]) mergeSort(a, b []E) {
 		}
 	}
 }
+
+func (lt lessFunc[E]) parallelSortFast(list []E, pool *workerPool) {
+	size := len(list)
+	a, b, c := size/4, size/2, size*3/4
+	a, ha := lt.median(list, a-1, a, a+1)
+	b, hb := lt.median(list, b-1, b, b+1)
+	c, hc := lt.median(list, c-1, c, c+1)
+	_, hint := lt.median(list, a, b, c)
+	hint &= ha & hb & hc
+
+	if hint == hintRevered {
+		reverse(list)
+		hint = hintSorted
+	}
+	if hint == hintSorted && lt.isSorted(list) {
+		return
+	}
+	lt.parallelSort(list, log2Ceil(uint(size))*3/2, pool)
+}
+
+func (lt lessFunc[E]) parallelSort(list []E, chance int, pool *workerPool) {
+	if len(list) <= parallelSize {
+		lt.introSort(list, chance)
+		return
+	}
+	if chance--; chance < 0 {
+		observe[E](eventHeapSort, len(list))
+		lt.heapSort(list)
+		return
+	}
+	l, r := lt.triPartition(list)
+	pool.fork(func() {
+		lt.parallelSort(list[:l], chance, pool)
+	}, func() {
+		lt.parallelSort(list[r+1:], chance, pool)
+	}, func() {
+		if lt(list[l], list[r]) {
+			lt.parallelSort(list[l+1:r], chance, pool)
+		}
+	})
+}
+
+func (lt lessFunc[E]) parallelMergeSort(a, b []E, pool *workerPool) {
+	size := len(a)
+	if size <= parallelSize {
+		lt.mergeSort(a, b)
+		return
+	}
+	half := size / 2
+	pool.fork(func() {
+		lt.parallelMergeSort(b[:half], a[:half], pool)
+	}, func() {
+		lt.parallelMergeSort(b[half:], a[half:], pool)
+	})
+	lt.parallelMerge(a[:half], a[half:], b, pool)
+}
+
+func (lt lessFunc[E]) parallelMerge(x, y, out []E, pool *workerPool) {
+	if len(x)+len(y) <= parallelSize {
+		i, j, k := 0, 0, 0
+		for ; i < len(x) && j < len(y); k++ {
+			if lt(y[j], x[i]) {
+				out[k] = y[j]
+				j++
+			} else {
+				out[k] = x[i]
+				i++
+			}
+		}
+		k += copy(out[k:], x[i:])
+		copy(out[k:], y[j:])
+		return
+	}
+
+	var i, j int
+	if len(x) >= len(y) {
+		i = len(x) / 2
+		pivot := x[i]
+		a, b := 0, len(y)
+		for a < b {
+			m := int(uint(a+b) / 2)
+			if lt(y[m], pivot) {
+				a = m + 1
+			} else {
+				b = m
+			}
+		}
+		j = a
+	} else {
+		j = len(y) / 2
+		pivot := y[j]
+		a, b := 0, len(x)
+		for a < b {
+			m := int(uint(a+b) / 2)
+			if lt(pivot, x[m]) {
+				b = m
+			} else {
+				a = m + 1
+			}
+		}
+		i = a
+	}
+	pool.fork(func() {
+		lt.parallelMerge(x[:i], y[:j], out[:i+j], pool)
+	}, func() {
+		lt.parallelMerge(x[i:], y[j:], out[i+j:], pool)
+	})
+}
+
+func (lt lessFunc[E]) mergeTo(out []E, lists [][]E) {
+	lists = append([][]E(nil), lists...)
+	heap := make([]int, 0, len(lists))
+	for i := 0; i < len(lists); i++ {
+		if len(lists[i]) != 0 {
+			heap = append(heap, i)
+		}
+	}
+	for i := len(heap)/2 - 1; i >= 0; i-- {
+		lt.mergeHeapDown(lists, heap, i)
+	}
+
+	k := 0
+	for len(heap) > 2 {
+		top := heap[0]
+		out[k] = lists[top][0]
+		k++
+		if lists[top] = lists[top][1:]; len(lists[top]) == 0 {
+			last := len(heap) - 1
+			heap[0] = heap[last]
+			heap = heap[:last]
+		}
+		lt.mergeHeapDown(lists, heap, 0)
+	}
+
+	switch len(heap) {
+	case 1:
+		copy(out[k:], lists[heap[0]])
+	case 2:
+		a, b := lists[heap[0]], lists[heap[1]]
+		if heap[1] < heap[0] {
+			a, b = b, a
+		}
+		i, j := 0, 0
+		for ; i < len(a) && j < len(b); k++ {
+			if lt(b[j], a[i]) {
+				out[k] = b[j]
+				j++
+			} else {
+				out[k] = a[i]
+				i++
+			}
+		}
+		k += copy(out[k:], a[i:])
+		copy(out[k:], b[j:])
+	}
+}
+
+func (lt lessFunc[E]) mergeBefore(lists [][]E, x, y int) bool {
+	if x < y {
+		return !lt(lists[y][0], lists[x][0])
+	}
+	return lt(lists[x][0], lists[y][0])
+}
+
+func (lt lessFunc[E]) mergeHeapDown(lists [][]E, heap []int, pos int) {
+	curr := heap[pos]
+	last := len(heap) - 1
+	for kid := pos*2 + 1; kid <= last; kid = pos*2 + 1 {
+		if kid < last && lt.mergeBefore(lists, heap[kid+1], heap[kid]) {
+			kid++
+		}
+		if !lt.mergeBefore(lists, heap[kid], curr) {
+			break
+		}
+		heap[pos] = heap[kid]
+		pos = kid
+	}
+	heap[pos] = curr
+}
+
+func (lt lessFunc[E]) mergeInPlace(list []E, border int) {
+	if border <= 0 || border >= len(list) {
+		return
+	}
+	a := lt.gallopUpper(list[:border], list[border])
+	if a == border {
+		return
+	}
+	b := len(list) - lt.gallopLowerTail(list[border:], list[border-1])
+	lt.symmerge(list[a:b], border-a)
+}
+
+func (lt lessFunc[E]) multiSelect(list []E, ks []int) {
+	lt.introSelect(list, ks, log2Ceil(uint(len(list)))*3/2)
+}
+
+func (lt lessFunc[E]) introSelect(list []E, ks []int, chance int) {
+	for len(ks) != 0 {
+		if len(list) <= 14 {
+			lt.simpleSort(list)
+			return
+		}
+		if chance--; chance < 0 {
+			observe[E](eventHeapSort, len(list))
+			lt.heapSort(list)
+			return
+		}
+		l, r := lt.triPartition(list)
+		a := 0
+		for a < len(ks) && ks[a] < l {
+			a++
+		}
+		b := a
+		for b < len(ks) && ks[b] <= r {
+			b++
+		}
+		lt.introSelect(list[:l], ks[:a], chance)
+
+		mid := ks[a:b]
+		if len(mid) != 0 && mid[0] == l {
+			mid = mid[1:]
+		}
+		if len(mid) != 0 && mid[len(mid)-1] == r {
+			mid = mid[:len(mid)-1]
+		}
+
+		if len(mid) != 0 && lt(list[l], list[r]) {
+			for i := 0; i < len(mid); i++ {
+				mid[i] -= l + 1
+			}
+			lt.introSelect(list[l+1:r], mid, chance)
+		}
+
+		ks = ks[b:]
+		for i := 0; i < len(ks); i++ {
+			ks[i] -= r + 1
+		}
+		list = list[r+1:]
+	}
+}
+
+func (lt lessFunc[E]) gallopLower(list []E, x E) int {
+	a, b := 0, 1
+	for b <= len(list) && lt(list[b-1], x) {
+		a, b = b, b*2
+	}
+	pos, _ := lt.binarySearch(list[a:min(b-1, len(list))], x)
+	return a + pos
+}
+
+func (lt lessFunc[E]) gallopUpper(list []E, x E) int {
+	a, b := 0, 1
+	for b <= len(list) && !lt(x, list[b-1]) {
+		a, b = b, b*2
+	}
+	for b = min(b-1, len(list)); a < b; {
+		m := int(uint(a+b) / 2)
+		if lt(x, list[m]) {
+			b = m
+		} else {
+			a = m + 1
+		}
+	}
+	return a
+}
+
+func (lt lessFunc[E]) appendUnique(dst, list []E) []E {
+	for len(list) != 0 {
+		dst = append(dst, list[0])
+		list = list[lt.gallopUpper(list[1:], list[0])+1:]
+	}
+	return dst
+}
+
+func (lt lessFunc[E]) compareFirst(a, b []E) int {
+	if lt(a[0], b[0]) {
+		return -1
+	}
+	if lt(b[0], a[0]) {
+		return 1
+	}
+	return 0
+}
+
+func (lt lessFunc[E]) setOperate(dst, a, b []E, op setOp, multi bool) []E {
+	keepA := op != setIntersect
+	keepB := op == setUnion || op == setSymmetricDifference
+	for len(a) != 0 && len(b) != 0 {
+		if c := lt.compareFirst(a, b); c < 0 {
+			n := lt.gallopLower(a[1:], b[0]) + 1
+			if keepA && multi {
+				dst = append(dst, a[:n]...)
+			} else if keepA {
+				dst = lt.appendUnique(dst, a[:n])
+			}
+			a = a[n:]
+		} else if c > 0 {
+			n := lt.gallopLower(b[1:], a[0]) + 1
+			if keepB && multi {
+				dst = append(dst, b[:n]...)
+			} else if keepB {
+				dst = lt.appendUnique(dst, b[:n])
+			}
+			b = b[n:]
+		} else {
+			na := lt.gallopUpper(a[1:], b[0]) + 1
+			nb := lt.gallopUpper(b[1:], a[0]) + 1
+			if !multi {
+				if op == setUnion || op == setIntersect {
+					dst = append(dst, a[0])
+				}
+			} else if op == setUnion {
+				dst = append(dst, a[:na]...)
+				if nb > na {
+					dst = append(dst, b[na:nb]...)
+				}
+			} else if op == setIntersect {
+				dst = append(dst, a[:min(na, nb)]...)
+			} else if na > nb {
+				dst = append(dst, a[nb:na]...)
+			} else if op == setSymmetricDifference && nb > na {
+				dst = append(dst, b[na:nb]...)
+			}
+			a, b = a[na:], b[nb:]
+		}
+	}
+	if keepA && multi {
+		dst = append(dst, a...)
+	} else if keepA {
+		dst = lt.appendUnique(dst, a)
+	}
+	if keepB && multi {
+		dst = append(dst, b...)
+	} else if keepB {
+		dst = lt.appendUnique(dst, b)
+	}
+	return dst
+}
+
+func (lt lessFunc[E]) isSubset(a, b []E, multi bool) bool {
+	for len(a) != 0 {
+		if multi && len(a) > len(b) {
+			return false
+		}
+		n, found := lt.gallopSearch(b, a[0], 0)
+		if !found {
+			return false
+		}
+		b = b[n:]
+		na := lt.gallopUpper(a[1:], a[0]) + 1
+		if multi {
+			nb := lt.gallopUpper(b[1:], a[0]) + 1
+			if nb < na {
+				return false
+			}
+			b = b[nb:]
+		}
+		a = a[na:]
+	}
+	return true
+}
+
+func (lt lessFunc[E]) compactSorted(list []E, counts []int, count bool) ([]E, []int) {
+	m := 0
+	for i := 0; i < len(list); m++ {
+		n := lt.gallopUpper(list[i+1:], list[i]) + 1
+		list[m] = list[i]
+		if count {
+			counts = append(counts, n)
+		}
+		i += n
+	}
+	return list[:m], counts
+}
+
+func (lt lessFunc[E]) gallopSearch(list []E, x E, hint int) (int, bool) {
+	hint = max(min(hint, len(list)), 0)
+	a := hint
+	if hint < len(list) && lt(list[hint], x) {
+		a += lt.gallopLower(list[hint+1:], x) + 1
+	} else {
+		b, step := hint, 1
+		for a = b - step; a >= 0 && !lt(list[a], x); a = b - step {
+			b = a
+			step *= 2
+		}
+		a = max(a+1, 0)
+		a += lt.lowerBound(list[a:b], x)
+	}
+	return a, a < len(list) && !lt(x, list[a])
+}
+
+func (lt lessFunc[E]) sortFastCancel(list []E, done cancelSignal) bool {
+	chance := log2Ceil(uint(len(list))) * 3 / 2
+	return lt.introSortCancel(list, chance, done)
+}
+
+func (lt lessFunc[E]) introSortCancel(list []E, chance int, done cancelSignal) bool {
+	for len(list) > cancelChunk {
+		if isDone(done) {
+			return false
+		}
+		if chance--; chance < 0 {
+			observe[E](eventHeapSort, len(list))
+			lt.heapSort(list)
+			return true
+		}
+		l, r := lt.triPartition(list)
+		if !lt.introSortCancel(list[:l], chance, done) ||
+			!lt.introSortCancel(list[r+1:], chance, done) {
+			return false
+		}
+		if !lt(list[l], list[r]) {
+			return true
+		}
+		list = list[l+1 : r]
+	}
+	if isDone(done) {
+		return false
+	}
+	lt.sortFast(list)
+	return true
+}
+
+func (lt lessFunc[E]) sortStableCancel(list []E, done cancelSignal) bool {
+	size := len(list)
+	b := lt.extendRun(list)
+	if b == size {
+		return true
+	}
+	buf := make([]E, size/2)
+	observe[E](eventAlloc, size/2)
+	return powerMerge(size, b, func(a int) int {
+		return a + lt.extendRun(list[a:])
+	}, func(a, b, c int) bool {
+		if c-a <= cancelChunk {
+			if a/cancelChunk != (c-1)/cancelChunk && isDone(done) {
+				return false
+			}
+			lt.mergeRuns(list[a:c], b-a, buf)
+			return true
+		}
+		return lt.mergeCancel(list[a:c], b-a, buf, done)
+	})
+}
+
+func (lt lessFunc[E]) mergeCancel(list []E, border int, buf []E, done cancelSignal) bool {
+	a := lt.gallopUpper(list[:border], list[border])
+	if a == border {
+		return true
+	}
+	b := len(list) - lt.gallopLowerTail(list[border:], list[border-1])
+	list, border = list[a:b], border-a
+
+	if border <= len(list)-border {
+
+		left := buf[:border]
+		copy(left, list[:border])
+		i, j, k := 0, border, 0
+		for i < len(left) && j < len(list) {
+			if isDone(done) {
+				break
+			}
+			for end := k + cancelChunk; k < end && i < len(left) && j < len(list); k++ {
+				if lt(list[j], left[i]) {
+					list[k] = list[j]
+					j++
+				} else {
+					list[k] = left[i]
+					i++
+				}
+			}
+		}
+		copy(list[k:], left[i:])
+		return i == len(left) || j == len(list)
+	}
+
+	right := buf[:len(list)-border]
+	copy(right, list[border:])
+	i, j, k := border-1, len(right)-1, len(list)-1
+	for i >= 0 && j >= 0 {
+		if isDone(done) {
+			break
+		}
+		for end := k - cancelChunk; k > end && i >= 0 && j >= 0; k-- {
+			if lt(right[j], list[i]) {
+				list[k] = list[i]
+				i--
+			} else {
+				list[k] = right[j]
+				j--
+			}
+		}
+	}
+	copy(list[i+1:], right[:j+1])
+	return i < 0 || j < 0
+}
+
+func (lt lessFunc[E]) pushBounded(heap []E, k int, x E) []E {
+	if len(heap) < k {
+		heap = append(heap, x)
+		if len(heap) == k {
+			for idx := k/2 - 1; idx >= 0; idx-- {
+				lt.heapDown(heap, idx)
+			}
+		}
+	} else if k > 0 && lt(x, heap[0]) {
+		heap[0] = x
+		lt.heapDown(heap, 0)
+	}
+	return heap
+}
+
+func (lt lessFunc[E]) pushGreatest(heap []E, k int, x E) []E {
+	if len(heap) < k {
+		heap = append(heap, x)
+		if len(heap) == k {
+			for idx := k/2 - 1; idx >= 0; idx-- {
+				lt.heapSiftDown(heap, idx, 1, nil, nil)
+			}
+		}
+	} else if k > 0 && lt(heap[0], x) {
+		heap[0] = x
+		lt.heapSiftDown(heap, 0, 1, nil, nil)
+	}
+	return heap
+}
+
+func (lt lessFunc[E]) heapSiftDown(list []E, pos int, shift uint, ids, index []int) int {
+	curr, id := list[pos], 0
+	if ids != nil {
+		id = ids[pos]
+	}
+	for {
+		kid := pos<<shift + 1
+		if kid >= len(list) {
+			break
+		}
+		end := min(kid+1<<shift, len(list))
+		for k := kid + 1; k < end; k++ {
+			if lt(list[k], list[kid]) {
+				kid = k
+			}
+		}
+		if !lt(list[kid], curr) {
+			break
+		}
+		list[pos] = list[kid]
+		if ids != nil {
+			ids[pos] = ids[kid]
+			index[ids[pos]] = pos
+		}
+		pos = kid
+	}
+	list[pos] = curr
+	if ids != nil {
+		ids[pos] = id
+		index[id] = pos
+	}
+	return pos
+}
+
+func (lt lessFunc[E]) heapSiftUp(list []E, pos int, shift uint, ids, index []int) int {
+	curr, id := list[pos], 0
+	if ids != nil {
+		id = ids[pos]
+	}
+	for pos > 0 {
+		parent := (pos - 1) >> shift
+		if !lt(curr, list[parent]) {
+			break
+		}
+		list[pos] = list[parent]
+		if ids != nil {
+			ids[pos] = ids[parent]
+			index[ids[pos]] = pos
+		}
+		pos = parent
+	}
+	list[pos] = curr
+	if ids != nil {
+		ids[pos] = id
+		index[id] = pos
+	}
+	return pos
+}